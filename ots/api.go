@@ -0,0 +1,32 @@
+package ots
+
+import "context"
+
+// API is the set of requests which can be made to OTS, implemented by *Client. Code which depends on OTS can
+// accept an API rather than a *Client, so that a mock can be substituted in tests. Methods may be added to API
+// as they are added to the OTS API, so mocks should embed API to remain compatible.
+type API interface {
+	Status() error
+	StatusContext(ctx context.Context) error
+
+	Create(secret, passphrase, recipient string, ttl int) (*Secret, error)
+	CreateContext(ctx context.Context, secret, passphrase, recipient string, ttl int) (*Secret, error)
+	CreateWithOptions(ctx context.Context, opts CreateOptions) (*Secret, error)
+
+	Generate(recipient, passphrase string, ttl int) (*Secret, error)
+	GenerateContext(ctx context.Context, recipient, passphrase string, ttl int) (*Secret, error)
+
+	Retrieve(secretKey, passphrase string) (*Secret, error)
+	RetrieveContext(ctx context.Context, secretKey, passphrase string) (*Secret, error)
+
+	RetrieveMetadata(metadataKey string) (*Secret, error)
+	RetrieveMetadataContext(ctx context.Context, metadataKey string) (*Secret, error)
+
+	Burn(metadataKey string) (*Secret, error)
+	BurnContext(ctx context.Context, metadataKey string) (*Secret, error)
+
+	RetrieveRecentMetadata() (*Secrets, error)
+	RetrieveRecentMetadataContext(ctx context.Context) (*Secrets, error)
+}
+
+var _ API = (*Client)(nil)
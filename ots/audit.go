@@ -0,0 +1,115 @@
+package ots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditEntry is a single line written to the audit log. It must never contain the secret value or passphrase.
+type auditEntry struct {
+	Time        time.Time `json:"time"`
+	Action      string    `json:"action"`
+	MetadataKey string    `json:"metadata_key"`
+	Recipient   string    `json:"recipient,omitempty"`
+	TTL         int       `json:"ttl,omitempty"`
+}
+
+// auditLog serialises writes of audit entries to the underlying writer.
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithAuditLog writes a JSON line to w for every successful create, generate and burn. Each line contains the time,
+// the action, the metadata key, the masked recipient and the TTL, but never the secret value or passphrase. Writes
+// are synchronised, so the client can be used concurrently with a single writer.
+//
+// The request has already succeeded when its entry is written, so a failed write does not fail the request. Use
+// WithAuditErrorHandler to be told about failed writes, without it they are only logged to the WithLogger logger.
+func WithAuditLog(w io.Writer) Option {
+	return func(c *Client) {
+		c.auditLog = &auditLog{w: w}
+	}
+}
+
+// WithAuditErrorHandler sets a function which is called when an entry cannot be written to the WithAuditLog writer,
+// so that a gap in the audit trail is not missed. It is called synchronously from the request's goroutine, so it
+// must be safe for concurrent use. A nil function, the default, leaves failed writes to the WithLogger logger.
+func WithAuditErrorHandler(fn func(err error)) Option {
+	return func(c *Client) {
+		c.auditErrorHandler = fn
+	}
+}
+
+// audit records the action in the audit log, if one is configured.
+func (c *Client) audit(action, metadataKey string, recipients []string, ttl int) {
+
+	if c.auditLog == nil {
+		return
+	}
+
+	line, err := json.Marshal(auditEntry{
+		Time:        time.Now().UTC(),
+		Action:      action,
+		MetadataKey: metadataKey,
+		Recipient:   maskEmails(recipients),
+		TTL:         ttl,
+	})
+	if err != nil {
+		c.auditFailed(action, metadataKey, err)
+		return
+	}
+
+	c.auditLog.mu.Lock()
+	defer c.auditLog.mu.Unlock()
+
+	if _, err := c.auditLog.w.Write(append(line, '\n')); err != nil {
+		c.auditFailed(action, metadataKey, err)
+	}
+}
+
+// auditFailed reports an audit entry which could not be written to the WithAuditErrorHandler function, or logs it
+// when there is none.
+func (c *Client) auditFailed(action, metadataKey string, err error) {
+
+	err = fmt.Errorf("ots: unable to write audit entry for %s of %s: %w", action, maskKey(metadataKey), err)
+
+	if c.auditErrorHandler == nil {
+		c.debug(context.Background(), "unable to write audit entry", "error", err)
+		return
+	}
+
+	c.auditErrorHandler(err)
+}
+
+// maskEmail obscures the local part of an email address, keeping the first character and the domain,
+// so that bob@example.com becomes b***@example.com.
+func maskEmail(email string) string {
+
+	if email == "" {
+		return ""
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+
+	return email[:1] + "***" + email[at:]
+}
+
+// maskEmails masks each email address with maskEmail, joining them with ", ".
+func maskEmails(emails []string) string {
+
+	masked := make([]string, len(emails))
+	for i, email := range emails {
+		masked[i] = maskEmail(email)
+	}
+
+	return strings.Join(masked, ", ")
+}
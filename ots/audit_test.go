@@ -0,0 +1,81 @@
+package ots
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey","secret_key":"secretkey"}`))
+
+	var buf bytes.Buffer
+	c := ts.client(WithAuditLog(&buf), WithAuditErrorHandler(func(err error) {
+		t.Errorf("unexpected audit error: %v", err)
+	}))
+
+	if _, err := c.Create("hunter2", "pass", "bob@example.com", 3600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	line := buf.String()
+	if strings.Contains(line, "hunter2") || strings.Contains(line, "pass") || strings.Contains(line, "bob@") {
+		t.Errorf("audit entry %q contains the secret, passphrase or recipient", line)
+	}
+
+	var entry auditEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("audit entry %q is not JSON: %v", line, err)
+	}
+	if entry.Action != "create" || entry.MetadataKey != "metakey" || entry.Recipient != "b***@example.com" || entry.TTL != 3600 {
+		t.Errorf("audit entry = %+v", entry)
+	}
+}
+
+// failingWriter is an io.Writer which always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestAuditLogWriteError(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+
+	var auditErr error
+	c := ts.client(WithAuditErrorHandler(func(err error) {
+		auditErr = err
+	}), WithAuditLog(failingWriter{}))
+
+	if _, err := c.Burn("metakey"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auditErr == nil || !strings.Contains(auditErr.Error(), "disk full") {
+		t.Errorf("audit error = %v, want the write error", auditErr)
+	}
+}
+
+func TestAuditLogWriteErrorWithoutHandler(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+	logger := &recordingLogger{}
+	c := ts.client(WithAuditLog(failingWriter{}), WithLogger(logger))
+
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error from Validate: %v", err)
+	}
+
+	if _, err := c.Burn("metakey"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.msgs) != 1 || logger.msgs[0] != "unable to write audit entry" {
+		t.Errorf("logs = %q, want the failed audit write", logger.msgs)
+	}
+}
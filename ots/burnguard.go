@@ -0,0 +1,100 @@
+package ots
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BurnGuard burns a created secret if the context it is watching is cancelled before the guard is released.
+// It is returned by BurnOnCancel.
+type BurnGuard struct {
+	mu       sync.Mutex
+	finished bool
+	burned   bool
+	err      error
+
+	release chan struct{}
+	done    chan struct{}
+}
+
+// BurnOnCancel watches ctx for up to window after a secret has been created. If ctx is cancelled within the window,
+// the secret is burned using its MetadataKey so that it does not outlive a failed operation. Once the secret has been
+// delivered, call Release on the returned guard to stop watching. If the window elapses first, the guard stops
+// watching and the secret is left as it is.
+//
+// The burn is sent independently of ctx, as ctx is already cancelled at that point. If the burn fails, the secret
+// may still be readable; the error is available from Err once Done is closed and the caller should decide how to
+// handle it, for example by retrying Burn.
+func (c *Client) BurnOnCancel(ctx context.Context, s *Secret, window time.Duration) *BurnGuard {
+
+	g := &BurnGuard{
+		release: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(g.done)
+
+		timer := time.NewTimer(window)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			if !g.finish() {
+				return
+			}
+			_, err := c.Burn(s.MetadataKey)
+
+			g.mu.Lock()
+			g.burned = err == nil
+			g.err = err
+			g.mu.Unlock()
+		case <-timer.C:
+			g.finish()
+		case <-g.release:
+		}
+	}()
+
+	return g
+}
+
+// Release stops the guard from burning the secret. It returns false if the guard had already finished, either
+// because the window elapsed or because a burn was started.
+func (g *BurnGuard) Release() bool {
+	if !g.finish() {
+		return false
+	}
+	close(g.release)
+	return true
+}
+
+// Done returns a channel which is closed once the guard has stopped watching and any burn has completed.
+func (g *BurnGuard) Done() <-chan struct{} {
+	return g.done
+}
+
+// Burned reports whether the secret was successfully burned by the guard.
+func (g *BurnGuard) Burned() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.burned
+}
+
+// Err returns the error from burning the secret, if a burn was attempted and failed.
+func (g *BurnGuard) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// finish marks the guard as finished, returning false if it already was.
+func (g *BurnGuard) finish() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.finished {
+		return false
+	}
+	g.finished = true
+	return true
+}
@@ -0,0 +1,24 @@
+package ots
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// retrievalCacheSalt separates keys produced by RetrievalCacheKey from other hashes of the same inputs.
+const retrievalCacheSalt = "onetimesecret-go/retrieval-cache-key/v1"
+
+// RetrievalCacheKey returns a stable key for a secretKey and passphrase pair which does not contain the passphrase,
+// so the same retrieval can be recognised across processes, for example to cache a not found result.
+// This is for cache coordination, not security: a short or guessable passphrase can be recovered from the key by
+// brute force, so the key should still be treated as sensitive and not be logged.
+func RetrievalCacheKey(secretKey, passphrase string) string {
+
+	mac := hmac.New(sha256.New, []byte(retrievalCacheSalt))
+	mac.Write([]byte(secretKey))
+	mac.Write([]byte{0})
+	mac.Write([]byte(passphrase))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,37 @@
+package ots
+
+// Capabilities reports which operations a client can perform given its credentials, as returned by Capabilities.
+type Capabilities struct {
+
+	// Create, Generate and Retrieve can be used anonymously or with credentials.
+	CanCreate   bool
+	CanGenerate bool
+	CanRetrieve bool
+
+	// OTS only emails recipients for an account, and not when WithForbidRecipients is used.
+	CanEmailRecipients bool
+
+	// The private metadata endpoints require an account.
+	CanRetrieveMetadata bool
+	CanBurn             bool
+	CanListRecent       bool
+}
+
+// Capabilities returns which operations the client supports with its current credentials, so that a UI can disable
+// unsupported actions rather than calling them and failing. This is computed locally from whether credentials are
+// set, the credentials themselves are not checked with the server.
+func (c *Client) Capabilities() Capabilities {
+
+	hasCredentials := !c.anonymous && c.hasCredentials()
+	canSend := c.anonymous || hasCredentials
+
+	return Capabilities{
+		CanCreate:           canSend,
+		CanGenerate:         canSend,
+		CanRetrieve:         canSend,
+		CanEmailRecipients:  hasCredentials && !c.forbidRecipients,
+		CanRetrieveMetadata: hasCredentials,
+		CanBurn:             hasCredentials,
+		CanListRecent:       hasCredentials,
+	}
+}
@@ -0,0 +1,108 @@
+package ots
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"strings"
+)
+
+// challengeIterations is the PBKDF2 iteration count used to hash new challenge answers. Answers are often short and
+// guessable, so a slow hash is needed to make guessing them from a stored record expensive.
+const challengeIterations = 600000
+
+// Challenge is a question which the recipient must answer to prove their identity before the passphrase is given to
+// them through another channel. The OTS API has no support for challenges, so it is never sent to the server. Instead
+// it is kept by the client in the secret's TrackingRecord, where the answer is stored only as a salted, slow hash.
+type Challenge struct {
+	Question string
+	Answer   string
+}
+
+// ChallengeRecord is the stored form of a Challenge, which can verify an answer without containing it.
+type ChallengeRecord struct {
+	Question   string
+	Salt       []byte
+	AnswerHash []byte
+
+	// The PBKDF2-HMAC-SHA256 iteration count the answer was hashed with. Zero for records created before answers
+	// were hashed with PBKDF2, whose AnswerHash is a single SHA-256 hash.
+	Iterations int
+}
+
+// newChallengeRecord hashes the answer of ch with a random salt.
+func newChallengeRecord(ch *Challenge) (*ChallengeRecord, error) {
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return &ChallengeRecord{
+		Question:   ch.Question,
+		Salt:       salt,
+		AnswerHash: hashAnswer(salt, ch.Answer, challengeIterations),
+		Iterations: challengeIterations,
+	}, nil
+}
+
+// Verify reports whether answer matches the challenge's answer. Surrounding whitespace and case are ignored.
+func (r *ChallengeRecord) Verify(answer string) bool {
+	if r == nil || len(r.AnswerHash) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hashAnswer(r.Salt, answer, r.Iterations), r.AnswerHash) == 1
+}
+
+// hashAnswer returns the PBKDF2-HMAC-SHA256 hash of the normalised answer with the salt, or the SHA-256 hash of the
+// salt and normalised answer when iterations is zero, as used by older records.
+func hashAnswer(salt []byte, answer string, iterations int) []byte {
+
+	normalised := []byte(strings.ToLower(strings.TrimSpace(answer)))
+
+	if iterations <= 0 {
+		h := sha256.New()
+		h.Write(salt)
+		h.Write(normalised)
+		return h.Sum(nil)
+	}
+
+	return pbkdf2SHA256(normalised, salt, iterations, sha256.Size)
+}
+
+// pbkdf2SHA256 derives a key of keyLen bytes from the password and salt with PBKDF2, as defined in RFC 8018, using
+// HMAC-SHA256 as the pseudorandom function.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var counter [4]byte
+	key := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		key = prf.Sum(key)
+
+		t := key[len(key)-hashLen:]
+		copy(u, t)
+
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,68 @@
+package ots
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+
+	tests := []struct {
+		password, salt string
+		iterations     int
+		keyLen         int
+		want           string
+	}{
+		{"password", "salt", 1, 32, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{"password", "salt", 2, 32, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{"password", "salt", 4096, 32, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+		{
+			"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 40,
+			"348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1c635518c7dac47e9",
+		},
+	}
+
+	for _, tt := range tests {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte(tt.password), []byte(tt.salt), tt.iterations, tt.keyLen))
+		if got != tt.want {
+			t.Errorf("pbkdf2SHA256(%q, %q, %d) = %s, want %s", tt.password, tt.salt, tt.iterations, got, tt.want)
+		}
+	}
+}
+
+func TestChallengeRecord(t *testing.T) {
+
+	r, err := newChallengeRecord(&Challenge{Question: "First pet?", Answer: " Rex "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.Iterations != challengeIterations {
+		t.Errorf("iterations = %d, want %d", r.Iterations, challengeIterations)
+	}
+
+	if !r.Verify("rex") {
+		t.Error("Verify rejected the answer")
+	}
+	if r.Verify("max") {
+		t.Error("Verify accepted the wrong answer")
+	}
+	if (*ChallengeRecord)(nil).Verify("rex") {
+		t.Error("Verify accepted an answer for a nil record")
+	}
+}
+
+func TestLegacyChallengeRecord(t *testing.T) {
+
+	salt := []byte("0123456789abcdef")
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte("rex"))
+
+	r := &ChallengeRecord{Question: "First pet?", Salt: salt, AnswerHash: h.Sum(nil)}
+
+	if !r.Verify("Rex") || r.Verify("max") {
+		t.Error("a record without an iteration count should verify with SHA-256")
+	}
+}
@@ -0,0 +1,114 @@
+package ots
+
+import "time"
+
+// ClientConfig is the non-sensitive, effective configuration of a Client, as returned by Config. It never
+// contains the username or token, so it is safe to log or include in a bug report.
+type ClientConfig struct {
+
+	// The API base which requests are sent to.
+	BaseURL string `json:"base_url"`
+
+	// The version of the OTS API, as set by WithAPIVersion, or empty when the version in BaseURL is used.
+	APIVersion APIVersion `json:"api_version,omitempty"`
+
+	// API bases which are tried when the previous base cannot be reached.
+	FallbackBaseURLs []string `json:"fallback_base_urls,omitempty"`
+
+	// Whether a username and token have been set, the values themselves are never included.
+	HasCredentials bool `json:"has_credentials"`
+
+	// Whether requests are sent without credentials.
+	Anonymous bool `json:"anonymous"`
+
+	// How recipients are encoded in share and generate requests.
+	RecipientEncoding RecipientEncoding `json:"recipient_encoding"`
+
+	// Whether recipients are forbidden, so secrets can only be shared by link.
+	ForbidRecipients bool `json:"forbid_recipients"`
+
+	// Whether requests are sent as a POST with the X-HTTP-Method-Override header.
+	MethodOverride bool `json:"method_override"`
+
+	// How long Status results are cached for, zero when caching is disabled.
+	StatusCacheTTL time.Duration `json:"status_cache_ttl"`
+
+	// The maximum size of a secret in bytes, zero when there is no limit.
+	MaxSecretSize int `json:"max_secret_size"`
+
+	// The longest TTL accepted by Create and Generate, zero when there is no limit.
+	MaxTTL time.Duration `json:"max_ttl"`
+
+	// Whether oversized secrets are truncated rather than rejected.
+	TruncateOversized bool `json:"truncate_oversized"`
+
+	// The overall timeout of a request, zero when there is none.
+	Timeout time.Duration `json:"timeout"`
+
+	// Timeouts for establishing a connection and the TLS handshake, zero when the net/http defaults are used.
+	DialTimeout         time.Duration `json:"dial_timeout"`
+	TLSHandshakeTimeout time.Duration `json:"tls_handshake_timeout"`
+
+	// How many times transient failures are retried, and the delay before the first retry.
+	MaxRetries   int           `json:"max_retries"`
+	RetryBackoff time.Duration `json:"retry_backoff"`
+
+	// The User-Agent header sent with requests.
+	UserAgent string `json:"user_agent"`
+
+	// Whether responses are decoded with json.Decoder.UseNumber.
+	UseNumber bool `json:"use_number"`
+
+	// Whether errors include the route of the request.
+	ErrorRoutes ErrorRoutes `json:"error_routes"`
+
+	// Whether the optional hooks have been configured.
+	HTTPTrace          bool `json:"http_trace"`
+	AuditLog           bool `json:"audit_log"`
+	PassphraseResolver bool `json:"passphrase_resolver"`
+	ResponseValidator  bool `json:"response_validator"`
+	MetricsHook        bool `json:"metrics_hook"`
+	Logger             bool `json:"logger"`
+}
+
+// Config returns the client's effective configuration, excluding credentials.
+func (c *Client) Config() ClientConfig {
+
+	cfg := ClientConfig{
+		BaseURL:             c.versionedBase(c.baseURL()),
+		APIVersion:          c.apiVersion,
+		FallbackBaseURLs:    append([]string(nil), c.fallbackBases...),
+		HasCredentials:      c.hasCredentials(),
+		Anonymous:           c.anonymous,
+		RecipientEncoding:   c.recipientEncoding,
+		MethodOverride:      c.methodOverride,
+		MaxSecretSize:       c.maxSecretSize,
+		MaxTTL:              c.maxTTL,
+		TruncateOversized:   c.truncateOversized,
+		MaxRetries:          c.maxRetries,
+		RetryBackoff:        c.retryBackoff,
+		UserAgent:           c.userAgentHeader(),
+		UseNumber:           c.useNumber,
+		ErrorRoutes:         c.errorRoutes,
+		DialTimeout:         c.dialTimeout,
+		TLSHandshakeTimeout: c.tlsHandshakeTimeout,
+		HTTPTrace:           c.traceFn != nil,
+		AuditLog:            c.auditLog != nil,
+		PassphraseResolver:  c.passphraseResolver != nil,
+		ResponseValidator:   c.responseValidator != nil,
+		MetricsHook:         c.metricsHook != nil,
+		Logger:              c.logger != nil,
+	}
+
+	cfg.Timeout = c.httpClient().Timeout
+
+	if cfg.MaxRetries > 0 && cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+
+	if c.statusCache != nil {
+		cfg.StatusCacheTTL = c.statusCache.ttl
+	}
+
+	return cfg
+}
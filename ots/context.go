@@ -0,0 +1,55 @@
+package ots
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type contextKey int
+
+const (
+	ttlKey contextKey = iota
+	requestIDKey
+)
+
+// ContextWithTTL returns a copy of ctx carrying a default TTL, in seconds, which CreateContext and GenerateContext
+// use when they are called with a TTL of 0. A TTL passed explicitly always takes precedence. This allows middleware
+// to set a TTL policy once for a request without passing it through every call.
+func ContextWithTTL(ctx context.Context, ttl int) context.Context {
+	return context.WithValue(ctx, ttlKey, ttl)
+}
+
+// ttlFromContext returns the TTL set by ContextWithTTL, or 0 if there is none.
+func ttlFromContext(ctx context.Context) int {
+	ttl, _ := ctx.Value(ttlKey).(int)
+	return ttl
+}
+
+// ContextWithRequestID returns a copy of ctx carrying a request ID, which is included in the client's log lines,
+// in any RequestError and in the X-Request-ID header, so that an operation can be traced end to end. When a request
+// is made without an ID, one is generated for it.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// withRequestID returns ctx with a newly generated request ID, unless it already carries one.
+func withRequestID(ctx context.Context) context.Context {
+
+	if RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ctx
+	}
+
+	return ContextWithRequestID(ctx, hex.EncodeToString(b))
+}
@@ -0,0 +1,27 @@
+package ots
+
+// SetCredentials replaces the username and token used by the client, for rotating an API token without creating a
+// new client and losing its connection pool. It is safe to call while requests are being made from other goroutines.
+//
+// Requests which have already been sent keep the credentials they were sent with. Any request sent after
+// SetCredentials returns, including a retry of an earlier request, uses the new credentials. Assigning Username and
+// Token directly is not safe while the client is in use, so use SetCredentials instead.
+func (c *Client) SetCredentials(user, token string) {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+	c.Username = user
+	c.Token = token
+}
+
+// credentials returns the username and token used by the client.
+func (c *Client) credentials() (user, token string) {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+	return c.Username, c.Token
+}
+
+// hasCredentials reports whether both a username and token have been set.
+func (c *Client) hasCredentials() bool {
+	user, token := c.credentials()
+	return user != "" && token != ""
+}
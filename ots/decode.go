@@ -0,0 +1,86 @@
+package ots
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// WithUseNumber decodes responses using json.Decoder.UseNumber, converting whole numbers such as timestamps and TTLs
+// to int64 exactly even when a server sends them in a float form like 1.7e9. A number which does not fit in an int64
+// returns an error rather than being silently corrupted. This guards against non-standard servers.
+func WithUseNumber() Option {
+	return func(c *Client) {
+		c.useNumber = true
+	}
+}
+
+// decode unmarshals a response body into v, normalising numbers first if the client uses WithUseNumber.
+func (c *Client) decode(body []byte, v interface{}) error {
+
+	if !c.useNumber {
+		return json.Unmarshal(body, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalised, err := normaliseNumbers(raw)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(normalised)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// normaliseNumbers rewrites every whole json.Number within v as a plain integer, returning an error for any which
+// overflow an int64. Numbers with a fractional part are left unchanged.
+func normaliseNumbers(v interface{}) (interface{}, error) {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			n, err := normaliseNumbers(e)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			t[k] = n
+		}
+	case []interface{}:
+		for i, e := range t {
+			n, err := normaliseNumbers(e)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = n
+		}
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return t, nil
+		}
+
+		f, _, err := big.ParseFloat(string(t), 10, 256, big.ToNearestEven)
+		if err != nil || !f.IsInt() {
+			return t, nil
+		}
+
+		i, acc := f.Int64()
+		if acc != big.Exact {
+			return nil, fmt.Errorf("number %s overflows int64", t)
+		}
+		return json.Number(fmt.Sprint(i)), nil
+	}
+
+	return v, nil
+}
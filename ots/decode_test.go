@@ -0,0 +1,34 @@
+package ots
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUseNumber(t *testing.T) {
+
+	body := `{"metadata_key":"metakey","created":1.700000001e9,"ttl":3.6e3}`
+
+	ts := newTestServer(t, respond(http.StatusOK, body))
+
+	if _, err := ts.client().RetrieveMetadata("metakey"); err == nil {
+		t.Error("expected an error decoding float timestamps without WithUseNumber")
+	}
+
+	s, err := ts.client(WithUseNumber()).RetrieveMetadata("metakey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Created != 1700000001 || s.TTL != 3600 {
+		t.Errorf("created, ttl = %d, %d, want 1700000001, 3600", s.Created, s.TTL)
+	}
+}
+
+func TestUseNumberOverflow(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey","created":1e30}`))
+
+	if _, err := ts.client(WithUseNumber()).RetrieveMetadata("metakey"); err == nil {
+		t.Error("expected an error for a timestamp which overflows an int64")
+	}
+}
@@ -0,0 +1,46 @@
+package ots
+
+import (
+	"fmt"
+	"os"
+)
+
+// Environment variables read by NewFromEnv, in order of preference.
+var (
+	envUsername = []string{"OTS_EMAIL", "OTS_USERNAME"}
+	envToken    = []string{"OTS_API_KEY", "OTS_KEY", "OTS_TOKEN"}
+)
+
+// NewFromEnv returns a client using credentials from the environment. The username (email) is read from OTS_EMAIL,
+// or OTS_USERNAME if that is not set, and the API token from OTS_API_KEY, falling back to OTS_KEY and then
+// OTS_TOKEN. If OTS_BASE_URL is set, requests are sent to that API base, such as https://ots.internal/api/v1.
+// An error wrapping ErrMissingCredentials is returned if the username or token is not set. Any options are
+// applied after those from the environment.
+func NewFromEnv(opts ...Option) (*Client, error) {
+
+	user := firstEnv(envUsername)
+	if user == "" {
+		return nil, fmt.Errorf("%w: none of %v are set", ErrMissingCredentials, envUsername)
+	}
+
+	token := firstEnv(envToken)
+	if token == "" {
+		return nil, fmt.Errorf("%w: none of %v are set", ErrMissingCredentials, envToken)
+	}
+
+	if baseURL := os.Getenv("OTS_BASE_URL"); baseURL != "" {
+		opts = append([]Option{WithBaseURL(baseURL)}, opts...)
+	}
+
+	return New(user, token, opts...), nil
+}
+
+// firstEnv returns the value of the first of the environment variables which is set and not empty.
+func firstEnv(names []string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,396 @@
+package ots
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// ErrMissingCredentials is returned before a request is sent when the client has an empty Username or Token
+// and has not been configured for anonymous use with WithAnonymous.
+var ErrMissingCredentials = errors.New("ots: missing username or token, set them on the client or use WithAnonymous")
+
+// ConfigError is returned by Validate and contains every problem found with the client's configuration.
+type ConfigError struct {
+	Problems []error
+}
+
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+	return "ots: invalid client configuration: " + strings.Join(msgs, "; ")
+}
+
+// Is allows errors.Is to match against any of the individual problems.
+func (e *ConfigError) Is(target error) bool {
+	return isAny(e.Problems, target)
+}
+
+// As allows errors.As to match against any of the individual problems.
+func (e *ConfigError) As(target interface{}) bool {
+	return asAny(e.Problems, target)
+}
+
+// isAny reports whether any of errs matches target, using errors.Is. Errors holding several errors use this
+// rather than an Unwrap() []error method, which errors.Is only follows from Go 1.20.
+func isAny(errs []error, target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// asAny finds the first of errs which matches target, using errors.As, and sets target to it.
+func asAny(errs []error, target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrPassphraseRequired is matched, using errors.Is, by the error from Retrieve when the secret needs a
+	// passphrase but none was given. The error also wraps the *RequestError and *APIError for the request.
+	ErrPassphraseRequired = errors.New("ots: a passphrase is required to retrieve this secret")
+
+	// ErrWrongPassphrase is matched, using errors.Is, by the error from Retrieve when the given passphrase is
+	// incorrect. The error also wraps the *RequestError and *APIError for the request.
+	ErrWrongPassphrase = errors.New("ots: incorrect passphrase")
+
+	// ErrSecretNotFound is matched, using errors.Is, by the error from Retrieve when the secret does not exist, as it
+	// has already been viewed, burned or has expired. The error also wraps the *APIError from the server.
+	ErrSecretNotFound = errors.New("ots: secret not found")
+)
+
+// secretNotFoundError wraps the error from a Retrieve for a secret which does not exist, so that it matches
+// ErrSecretNotFound while still unwrapping to the original error.
+type secretNotFoundError struct {
+	err error
+}
+
+func (e *secretNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+// Is reports whether target is ErrSecretNotFound.
+func (e *secretNotFoundError) Is(target error) bool {
+	return target == ErrSecretNotFound
+}
+
+func (e *secretNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// passphraseError wraps the error from a Retrieve which failed because of the passphrase, so that it matches
+// ErrPassphraseRequired or ErrWrongPassphrase while still unwrapping to the *RequestError and *APIError.
+type passphraseError struct {
+	sentinel error
+	err      error
+}
+
+func (e *passphraseError) Error() string {
+	return e.sentinel.Error() + ": " + e.err.Error()
+}
+
+// Is reports whether target is the passphrase error this wraps.
+func (e *passphraseError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *passphraseError) Unwrap() error {
+	return e.err
+}
+
+// APIError is returned when the OTS API responds with an error status code, or with a message rather than the
+// expected response. Use errors.As to inspect it, for example to check for a 404 when a secret has already been viewed.
+type APIError struct {
+
+	// The HTTP status code of the response.
+	StatusCode int
+
+	// The message from the response body, such as "Unknown secret". Empty if the body had no message.
+	Message string
+
+	// The raw response body.
+	Raw []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ots: unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("ots: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// MultiError is returned when the OTS API responds with several messages at once, such as when more than one
+// input is invalid. It contains an *APIError for each message, so errors.As finds the first of them.
+type MultiError struct {
+	Errors []*APIError
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 0 {
+		return "ots: unknown errors"
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Message
+	}
+	return fmt.Sprintf("ots: %s (status %d)", strings.Join(msgs, "; "), e.Errors[0].StatusCode)
+}
+
+// Is allows errors.Is to match against any of the individual errors.
+func (e *MultiError) Is(target error) bool {
+	return isAny(e.errors(), target)
+}
+
+// As allows errors.As to match against any of the individual errors.
+func (e *MultiError) As(target interface{}) bool {
+	return asAny(e.errors(), target)
+}
+
+func (e *MultiError) errors() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// checkResponse returns an *APIError if the status code is not 2xx or the body contains a message, or a
+// *MultiError if it contains several.
+func checkResponse(statusCode int, body []byte) error {
+
+	msgs := responseMessages(body)
+
+	if statusCode >= 200 && statusCode < 300 && len(msgs) == 0 {
+		return nil
+	}
+
+	if len(msgs) <= 1 {
+		apiErr := &APIError{StatusCode: statusCode, Raw: body}
+		if len(msgs) == 1 {
+			apiErr.Message = msgs[0]
+		}
+		return apiErr
+	}
+
+	multi := &MultiError{}
+	for _, msg := range msgs {
+		multi.Errors = append(multi.Errors, &APIError{StatusCode: statusCode, Message: msg, Raw: body})
+	}
+
+	return multi
+}
+
+// responseMessages returns the messages in a response body, which the server may give as a single message, a
+// newline-delimited list or an array of messages.
+func responseMessages(body []byte) []string {
+
+	var resp struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Message) == 0 {
+		return nil
+	}
+
+	var lines []string
+
+	var one string
+	if err := json.Unmarshal(resp.Message, &one); err == nil {
+		lines = strings.Split(one, "\n")
+	} else if err := json.Unmarshal(resp.Message, &lines); err != nil {
+		return nil
+	}
+
+	var msgs []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			msgs = append(msgs, line)
+		}
+	}
+
+	return msgs
+}
+
+// retrieveError maps a failed Retrieve to an error matching ErrPassphraseRequired or ErrWrongPassphrase when the
+// server's message is about the passphrase, depending on whether one was given, or to an error matching
+// ErrSecretNotFound when the secret does not exist. Either way err is still wrapped. Other errors are returned
+// unchanged.
+func retrieveError(err error, passphrase string) error {
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	if !strings.Contains(strings.ToLower(apiErr.Message), "passphrase") {
+		if isNotFound(err) {
+			return &secretNotFoundError{err: err}
+		}
+		return err
+	}
+
+	if passphrase == "" {
+		return &passphraseError{sentinel: ErrPassphraseRequired, err: err}
+	}
+
+	return &passphraseError{sentinel: ErrWrongPassphrase, err: err}
+}
+
+// isNotFound reports whether the error is the server saying that a secret or its metadata does not exist.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "unknown") || strings.Contains(msg, "not found")
+}
+
+// ErrEmptySecret is returned by Retrieve when the server responds successfully but without the secret's value, which
+// some servers do for a secret which has already been viewed. The error returned matches it using errors.Is, along
+// with ErrSecretNotFound, and is wrapped in a *RequestError.
+var ErrEmptySecret = errors.New("ots: the server returned no value, the secret may have already been viewed")
+
+// ErrSecretTooLarge is returned by Create when the secret is larger than the size set by WithMaxSecretSize.
+var ErrSecretTooLarge = errors.New("ots: secret is too large")
+
+// ErrInvalidTTL is returned by Create and Generate when the TTL is negative or longer than the maximum set by WithMaxTTL.
+var ErrInvalidTTL = errors.New("ots: invalid TTL")
+
+// ErrInvalidSecretKey is returned when a value is neither a secret key nor a share URL.
+var ErrInvalidSecretKey = errors.New("ots: invalid secret key")
+
+// ErrUntrustedURL is returned by RetrieveAny when given a share URL for a host which the client is not configured to use.
+var ErrUntrustedURL = errors.New("ots: share URL is not for a trusted host")
+
+// ErrPreValidationUnsupported is returned by RetrieveWithCandidates when a passphrase cannot be checked without
+// consuming the secret, as the OTS API has no endpoint for doing so.
+var ErrPreValidationUnsupported = errors.New("ots: the server cannot check a passphrase without consuming the secret")
+
+// ErrMetadataUnstable is returned by RetrieveStableMetadata when the state kept changing until polling was stopped.
+var ErrMetadataUnstable = errors.New("ots: metadata state did not settle")
+
+// ErrRecipientForbidden is returned by Create and Generate when a recipient is given to a client created with
+// WithForbidRecipients.
+var ErrRecipientForbidden = errors.New("ots: recipients are forbidden, secrets must be shared by link")
+
+// ErrInvalidRecipient is matched, using errors.Is, by the *RecipientError returned by Create and Generate when a
+// recipient is not a valid email address. Only a bare address is valid, so "Bob <bob@example.com>" is rejected.
+var ErrInvalidRecipient = errors.New("ots: invalid recipient")
+
+// RecipientError is returned by Create and Generate when a recipient is not a valid email address. It is returned
+// before a request is sent. When several recipients are invalid, a *RecipientsError is returned instead.
+type RecipientError struct {
+	Recipient string
+
+	// The error from parsing the address.
+	Err error
+}
+
+func (e *RecipientError) Error() string {
+	return fmt.Sprintf("ots: invalid recipient %q: %v", e.Recipient, e.Err)
+}
+
+// Is reports whether target is ErrInvalidRecipient.
+func (e *RecipientError) Is(target error) bool {
+	return target == ErrInvalidRecipient
+}
+
+// Unwrap returns the error from parsing the address.
+func (e *RecipientError) Unwrap() error {
+	return e.Err
+}
+
+// RecipientsError is returned by CreateWithOptions when more than one recipient is not a valid email address.
+// It contains a *RecipientError for each of them, so errors.Is matches ErrInvalidRecipient.
+type RecipientsError struct {
+	Errors []*RecipientError
+}
+
+func (e *RecipientsError) Error() string {
+	names := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		names[i] = fmt.Sprintf("%q", err.Recipient)
+	}
+	return "ots: invalid recipients: " + strings.Join(names, ", ")
+}
+
+// Is allows errors.Is to match against the error for any of the recipients.
+func (e *RecipientsError) Is(target error) bool {
+	return isAny(e.errors(), target)
+}
+
+// As allows errors.As to match against the error for any of the recipients.
+func (e *RecipientsError) As(target interface{}) bool {
+	return asAny(e.errors(), target)
+}
+
+func (e *RecipientsError) errors() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// errRecipientNotBare is the parse error for a recipient which is a valid address but not a bare one, such as
+// "Bob <bob@example.com>". The recipient is sent to the server as given, so only the bare address is accepted.
+var errRecipientNotBare = errors.New("mail: expected a bare address without a display name or angle brackets")
+
+// checkRecipients returns a *RecipientError if one of the recipients is not a bare, valid email address, or a
+// *RecipientsError naming each of them if there is more than one.
+func checkRecipients(recipients []string) error {
+
+	var invalid []*RecipientError
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r)
+		if err == nil && addr.Address != r {
+			err = errRecipientNotBare
+		}
+		if err != nil {
+			invalid = append(invalid, &RecipientError{Recipient: r, Err: err})
+		}
+	}
+
+	switch len(invalid) {
+	case 0:
+		return nil
+	case 1:
+		return invalid[0]
+	default:
+		return &RecipientsError{Errors: invalid}
+	}
+}
+
+// ErrServerOffline is matched, using errors.Is, by the *OfflineError returned by Status when the server is offline.
+var ErrServerOffline = errors.New("ots: server is offline")
+
+// OfflineError is returned by Status when the server reports that it is offline.
+type OfflineError struct {
+
+	// The reason given by the server, such as planned maintenance, or a generic reason if it gave none.
+	Reason string
+}
+
+func (e *OfflineError) Error() string {
+	return "ots: server is offline: " + e.Reason
+}
+
+// Is reports whether target is ErrServerOffline.
+func (e *OfflineError) Is(target error) bool {
+	return target == ErrServerOffline
+}
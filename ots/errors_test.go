@@ -0,0 +1,197 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRetrievePassphraseErrors(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		passphrase string
+		want       error
+	}{
+		{name: "no passphrase", passphrase: "", want: ErrPassphraseRequired},
+		{name: "wrong passphrase", passphrase: "wrong", want: ErrWrongPassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(http.StatusNotFound, `{"message":"Double check that passphrase"}`))
+
+			ctx := ContextWithRequestID(context.Background(), "req-1")
+			_, err := ts.client().RetrieveContext(ctx, "secretkey", tt.passphrase)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) || reqErr.RequestID != "req-1" {
+				t.Errorf("error = %v, want a *RequestError with the request ID", err)
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				t.Errorf("error = %v, want the *APIError from the server", err)
+			}
+		})
+	}
+}
+
+func TestInvalidRecipients(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+	c := ts.client()
+
+	_, err := c.Create("hunter2", "", "not an email", 60)
+
+	var recipientErr *RecipientError
+	if !errors.As(err, &recipientErr) || recipientErr.Recipient != "not an email" {
+		t.Errorf("error = %v, want a *RecipientError for the recipient", err)
+	}
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("errors.Is(%v, ErrInvalidRecipient) = false, want true", err)
+	}
+
+	for _, r := range []string{"Bob <bob@example.com>", "<bob@example.com>", " bob@example.com"} {
+		_, err := c.Create("hunter2", "", r, 60)
+		if !errors.As(err, &recipientErr) || recipientErr.Recipient != r || !errors.Is(err, ErrInvalidRecipient) {
+			t.Errorf("error for %q = %v, want a *RecipientError as only bare addresses are sent", r, err)
+		}
+	}
+
+	if n := ts.count(); n != 0 {
+		t.Errorf("the server received %d requests, want 0", n)
+	}
+
+	if _, err := c.Create("hunter2", "", "bob@example.com", 60); err != nil {
+		t.Errorf("unexpected error for a valid address: %v", err)
+	}
+	if got := ts.last(t).Form.Get("recipient"); got != "bob@example.com" {
+		t.Errorf("recipient = %q, want bob@example.com", got)
+	}
+}
+
+func TestRecipientsError(t *testing.T) {
+
+	_, err := New("user@example.com", "token").CreateWithOptions(context.Background(), CreateOptions{
+		Secret:     "hunter2",
+		Recipient:  "bob@example.com",
+		Recipients: []string{"first", "second"},
+	})
+
+	var recipientsErr *RecipientsError
+	if !errors.As(err, &recipientsErr) || len(recipientsErr.Errors) != 2 {
+		t.Fatalf("error = %v, want a *RecipientsError for both invalid recipients", err)
+	}
+
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("errors.Is(%v, ErrInvalidRecipient) = false, want true", err)
+	}
+
+	var recipientErr *RecipientError
+	if !errors.As(err, &recipientErr) || recipientErr.Recipient != "first" {
+		t.Errorf("errors.As found %v, want the error for the first recipient", recipientErr)
+	}
+}
+
+func TestResponseMessages(t *testing.T) {
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "single", body: `{"message":"Unknown secret"}`, want: []string{"Unknown secret"}},
+		{name: "lines", body: `{"message":"Secret is too long\nTTL is too long"}`, want: []string{"Secret is too long", "TTL is too long"}},
+		{name: "array", body: `{"message":["Secret is too long"," ","TTL is too long"]}`, want: []string{"Secret is too long", "TTL is too long"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(http.StatusBadRequest, tt.body))
+
+			_, err := ts.client().Create("hunter2", "", "", 60)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Message != tt.want[0] || apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("errors.As found %v, want an *APIError for %q", apiErr, tt.want[0])
+			}
+
+			var multi *MultiError
+			if errors.As(err, &multi) != (len(tt.want) > 1) {
+				t.Fatalf("error = %T, want a *MultiError only for several messages", err)
+			}
+			if multi == nil {
+				return
+			}
+
+			if len(multi.Errors) != len(tt.want) {
+				t.Fatalf("errors = %v, want %q", multi.Errors, tt.want)
+			}
+			for i, e := range multi.Errors {
+				if e.Message != tt.want[i] {
+					t.Errorf("message %d = %q, want %q", i, e.Message, tt.want[i])
+				}
+			}
+			if want := "ots: Secret is too long; TTL is too long (status 400)"; multi.Error() != want {
+				t.Errorf("Error() = %q, want %q", multi.Error(), want)
+			}
+			if !errors.Is(err, multi.Errors[1]) {
+				t.Error("errors.Is did not match the second error")
+			}
+		})
+	}
+}
+
+func TestEmptyMultiError(t *testing.T) {
+
+	err := &MultiError{}
+
+	if err.Error() == "" {
+		t.Error("Error() is empty")
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Error("errors.As matched an *APIError in an empty MultiError")
+	}
+}
+
+func TestRetrieveSecretNotFound(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "unknown secret", status: http.StatusNotFound, body: `{"message":"Unknown secret"}`},
+		{name: "not found status", status: http.StatusNotFound, body: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(tt.status, tt.body))
+
+			_, err := ts.client().Retrieve("secretkey", "pass")
+			if !errors.Is(err, ErrSecretNotFound) {
+				t.Errorf("error = %v, want ErrSecretNotFound", err)
+			}
+			if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrPassphraseRequired) {
+				t.Errorf("error = %v, want it not to be a passphrase error", err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("error = %v, want it to wrap the *APIError", err)
+			}
+		})
+	}
+
+	ts := newTestServer(t, respond(http.StatusInternalServerError, `{"message":"Server error"}`))
+	if _, err := ts.client().Retrieve("secretkey", ""); errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("a server error matched ErrSecretNotFound: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package ots
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EventType is the kind of operation an Event describes.
+type EventType string
+
+const (
+	// EventCreated is published after a successful Create.
+	EventCreated EventType = "created"
+
+	// EventGenerated is published after a successful Generate.
+	EventGenerated EventType = "generated"
+
+	// EventBurned is published after a successful Burn.
+	EventBurned EventType = "burned"
+
+	// EventViewed is published when WaitUntilViewed sees that a secret has been viewed.
+	EventViewed EventType = "viewed"
+)
+
+// Event is published on the channel returned by Events.
+type Event struct {
+	Type   EventType
+	Secret *Secret
+	Time   time.Time
+}
+
+// WithEvents enables publishing of events to the channel returned by Events, buffered to hold up to buffer events.
+// Events are published without blocking, so when a consumer is too slow and the buffer is full, new events are
+// dropped rather than delaying the client.
+func WithEvents(buffer int) Option {
+	return func(c *Client) {
+		c.events = make(chan Event, buffer)
+	}
+}
+
+// Events returns the channel which events are published to. This is nil unless the client was created with
+// WithEvents, and receiving from a nil channel blocks forever.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// publish sends an event if events are enabled, dropping it if the buffer is full.
+func (c *Client) publish(t EventType, s *Secret) {
+
+	if c.events == nil {
+		return
+	}
+
+	select {
+	case c.events <- Event{Type: t, Secret: s, Time: time.Now().UTC()}:
+	default:
+	}
+}
+
+// WaitUntilViewed polls the metadata of a secret every interval until it has been viewed by the recipient, then
+// returns the metadata. An error is returned if the secret is burned, a request fails or ctx is done, or without
+// sending a request if interval is not positive.
+func (c *Client) WaitUntilViewed(ctx context.Context, metadataKey string, interval time.Duration) (*Secret, error) {
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("ots: poll interval must be positive, got %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m, err := c.RetrieveMetadataContext(ctx, metadataKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Viewed() {
+			c.publish(EventViewed, m)
+			return m, nil
+		}
+		if m.State == "burned" {
+			return nil, fmt.Errorf("ots: secret was burned before it was viewed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ots
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWaitUntilViewed(t *testing.T) {
+
+	polls := 0
+	ts := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			respond(http.StatusOK, `{"metadata_key":"metakey","state":"new"}`)(w, r)
+			return
+		}
+		respond(http.StatusOK, `{"metadata_key":"metakey","state":"received","received":1700000100}`)(w, r)
+	})
+
+	c := ts.client(WithEvents(1))
+
+	s, err := c.WaitUntilViewed(context.Background(), "metakey", time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.Viewed() || ts.count() != 3 {
+		t.Errorf("secret = %v after %d polls, want viewed after 3", s, ts.count())
+	}
+
+	if e := <-c.Events(); e.Type != EventViewed || e.Secret.MetadataKey != "metakey" {
+		t.Errorf("event = %+v, want EventViewed", e)
+	}
+}
+
+func TestWaitUntilViewedInvalidInterval(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey","state":"new"}`))
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := ts.client().WaitUntilViewed(context.Background(), "metakey", interval); err == nil {
+			t.Errorf("expected an error for an interval of %s", interval)
+		}
+	}
+
+	if n := ts.count(); n != 0 {
+		t.Errorf("the server received %d requests, want 0", n)
+	}
+}
@@ -0,0 +1,56 @@
+package ots
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiredMetadata returns the recent metadata whose secret has passed its TTL, but which is still being returned
+// by the server. The expiry is calculated from when each secret was created and the TTL it was created with, so
+// secrets which have already been viewed or burned are not included. An empty slice is returned when none have expired.
+//
+// Nothing is burned by this method, pass the result to BurnExpired to clean them up.
+func (c *Client) ExpiredMetadata(ctx context.Context) (Secrets, error) {
+
+	recent, err := c.RetrieveRecentMetadataContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expired := Secrets{}
+
+	if recent != nil {
+		for _, s := range *recent {
+			if s.Viewed() || s.State == "burned" || s.Created == 0 || s.TTL <= 0 {
+				continue
+			}
+			if !s.CreatedTime().Add(time.Duration(s.TTL) * time.Second).After(now) {
+				expired = append(expired, s)
+			}
+		}
+	}
+
+	return expired, nil
+}
+
+// BurnExpired burns each of the given secrets, usually the result of ExpiredMetadata, using their MetadataKey.
+// Secrets which the server no longer knows about are skipped. It stops at the first other error, returning the
+// secrets which were burned before it.
+func (c *Client) BurnExpired(ctx context.Context, expired Secrets) (Secrets, error) {
+
+	burned := Secrets{}
+
+	for _, s := range expired {
+		b, err := c.BurnContext(ctx, s.MetadataKey)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return burned, err
+		}
+		burned = append(burned, *b)
+	}
+
+	return burned, nil
+}
@@ -0,0 +1,65 @@
+package ots
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithRequestHook sets a function which is called before every HTTP request is sent, including retries and
+// requests to fallback bases, such as for starting a tracing span. It is given a copy of the request without its
+// body or Authorization header, so secrets and credentials are never exposed, and changes to it have no effect.
+// Its URL includes the secret or metadata key for requests about a single secret. The function must be safe for
+// concurrent use.
+func WithRequestHook(fn func(req *http.Request)) Option {
+	return func(c *Client) {
+		c.requestHook = fn
+	}
+}
+
+// WithResponseHook sets a function which is called after every HTTP response is received, with how long it took
+// to receive the response headers, such as for recording latency and status codes. It is given a copy of the
+// response without its body, so secrets are never exposed. It is not called when no response was received.
+// The function must be safe for concurrent use.
+func WithResponseHook(fn func(resp *http.Response, d time.Duration)) Option {
+	return func(c *Client) {
+		c.responseHook = fn
+	}
+}
+
+// hookRequest calls the request hook, if one is set, with a copy of req that has no body or credentials.
+func (c *Client) hookRequest(req *http.Request) {
+
+	if c.requestHook == nil {
+		return
+	}
+
+	c.requestHook(redactRequest(req))
+}
+
+// hookResponse calls the response hook, if one is set, with a copy of resp that has no body.
+func (c *Client) hookResponse(resp *http.Response, d time.Duration) {
+
+	if c.responseHook == nil {
+		return
+	}
+
+	r := *resp
+	r.Body = http.NoBody
+	r.Header = resp.Header.Clone()
+	if resp.Request != nil {
+		r.Request = redactRequest(resp.Request)
+	}
+
+	c.responseHook(&r, d)
+}
+
+// redactRequest returns a copy of req without its body or Authorization header.
+func redactRequest(req *http.Request) *http.Request {
+
+	r := req.Clone(req.Context())
+	r.Body = http.NoBody
+	r.GetBody = nil
+	r.Header.Del("Authorization")
+
+	return r
+}
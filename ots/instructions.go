@@ -0,0 +1,84 @@
+package ots
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// InstructionData is the data a template is executed with by InstructionText and InstructionTextTemplate.
+type InstructionData struct {
+
+	// The link the recipient visits to view the secret.
+	Link string
+
+	// When the secret expires, this is zero if unknown.
+	ExpiresAt time.Time
+
+	// A human readable form of the time remaining until ExpiresAt, such as "24 hours". Empty if ExpiresAt is zero.
+	ExpiresIn string
+
+	// Whether the recipient needs a passphrase to view the secret.
+	PassphraseRequired bool
+}
+
+// defaultInstructionTemplate is the template used by InstructionText.
+var defaultInstructionTemplate = template.Must(template.New("instructions").Parse(
+	`Open this link{{if .ExpiresIn}} within {{.ExpiresIn}}{{end}} to view the secret, it can only be viewed once:
+{{.Link}}
+{{- if .PassphraseRequired}}
+The passphrase was sent separately.{{end}}`))
+
+// InstructionText returns a human friendly message to give to the recipient of the secret, containing the link to
+// view it, when it expires and whether a passphrase is needed. The baseURL is handled in the same way as BuildShareURL.
+// Use InstructionTextTemplate to change the wording of the message.
+func (s *Secret) InstructionText(baseURL string, expiresAt time.Time) string {
+	return s.InstructionTextTemplate(defaultInstructionTemplate, baseURL, expiresAt)
+}
+
+// InstructionTextTemplate is the same as InstructionText, but renders the message from tmpl, which is executed with
+// an InstructionData. An empty string is returned if the template fails to execute.
+func (s *Secret) InstructionTextTemplate(tmpl *template.Template, baseURL string, expiresAt time.Time) string {
+
+	data := InstructionData{
+		Link:               BuildShareURL(baseURL, s.SecretKey),
+		ExpiresAt:          expiresAt,
+		PassphraseRequired: s.PassphraseRequired,
+	}
+
+	if !expiresAt.IsZero() {
+		data.ExpiresIn = humanDuration(time.Until(expiresAt))
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
+
+// humanDuration formats d using its largest whole unit, such as "3 days" or "45 minutes".
+func humanDuration(d time.Duration) string {
+
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	d = d.Round(time.Minute)
+
+	switch {
+	case d >= 48*time.Hour:
+		return plural(int64(d/(24*time.Hour)), "day")
+	case d >= 2*time.Hour:
+		return plural(int64(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return plural(int64(d/time.Minute), "minute")
+	default:
+		return "less than a minute"
+	}
+}
@@ -0,0 +1,53 @@
+package ots
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestInstructionText(t *testing.T) {
+
+	s := &Secret{SecretKey: "secretkey", PassphraseRequired: true}
+
+	got := s.InstructionText("https://ots.internal/api/v1", time.Now().Add(24*time.Hour+30*time.Second))
+
+	want := "Open this link within 24 hours to view the secret, it can only be viewed once:\n" +
+		"https://ots.internal/secret/secretkey\n" +
+		"The passphrase was sent separately."
+	if got != want {
+		t.Errorf("InstructionText = %q, want %q", got, want)
+	}
+}
+
+func TestInstructionTextTemplate(t *testing.T) {
+
+	s := &Secret{SecretKey: "secretkey"}
+	tmpl := template.Must(template.New("custom").Parse(`View {{.Link}}{{if .PassphraseRequired}} with the passphrase{{end}}`))
+
+	// Rendering with different templates at once must not interfere.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if got := s.InstructionTextTemplate(tmpl, "", time.Time{}); got != "View https://onetimesecret.com/secret/secretkey" {
+				t.Errorf("InstructionTextTemplate = %q", got)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if got := s.InstructionText("", time.Time{}); !strings.HasPrefix(got, "Open this link to view") {
+				t.Errorf("InstructionText = %q", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	broken := template.Must(template.New("broken").Parse(`{{.Missing}}`))
+	if got := s.InstructionTextTemplate(broken, "", time.Time{}); got != "" {
+		t.Errorf("InstructionTextTemplate with a failing template = %q, want empty", got)
+	}
+}
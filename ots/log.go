@@ -0,0 +1,30 @@
+package ots
+
+import "context"
+
+// Logger receives debug logs from the client, such as why a request failed. The args are alternating keys and
+// values, so a *slog.Logger can be used directly. Logs never include a secret's value or passphrase.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// WithLogger sets the logger which the client writes debug logs to. By default, nothing is logged.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// debug writes a log to the logger set by WithLogger, if any, including the request ID from ctx when there is one.
+func (c *Client) debug(ctx context.Context, msg string, args ...interface{}) {
+
+	if c.logger == nil {
+		return
+	}
+
+	if id := RequestIDFromContext(ctx); id != "" {
+		args = append([]interface{}{"request_id", id}, args...)
+	}
+
+	c.logger.Debug(msg, args...)
+}
@@ -0,0 +1,119 @@
+package ots
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestMetrics describes a single HTTP request to the OTS API, as passed to the function given to WithMetricsHook.
+type RequestMetrics struct {
+	Method string
+
+	// The route with any keys replaced by ":key", such as "secret/:key", so it is safe to use as a metric label.
+	Route string
+
+	// The HTTP status code of the response, zero if no response was received.
+	StatusCode int
+
+	// How long it took to receive the response headers.
+	Duration time.Duration
+
+	// The error from sending the request, nil if a response was received.
+	Err error
+
+	// The size of the request and response bodies.
+	Bytes ByteCounts
+}
+
+// ByteCounts is the number of body bytes sent and received by a request, excluding headers.
+type ByteCounts struct {
+	Sent int64
+
+	// The number of bytes read from the response body. If the body was not read to the end, such as when a
+	// response could not be decoded, this is only what was read.
+	Received int64
+}
+
+// WithMetricsHook registers a function which is called after every HTTP request to the OTS API, including each
+// attempt against a fallback base, so that request counts, errors, latency and bytes can be recorded. When a
+// response is received, the function is called once its body has been closed, so that the bytes received are known.
+// The function is called from the goroutine making the request and must be safe for concurrent use.
+func WithMetricsHook(fn func(RequestMetrics)) Option {
+	return func(c *Client) {
+		c.metricsHook = fn
+	}
+}
+
+// observe reports a request which did not receive a response to the metrics hook, if one is configured.
+func (c *Client) observe(method, routePath string, start time.Time, sent int64, err error) {
+
+	if c.metricsHook == nil {
+		return
+	}
+
+	c.metricsHook(RequestMetrics{
+		Method:   method,
+		Route:    routeTemplate(routePath),
+		Duration: time.Since(start),
+		Err:      err,
+		Bytes:    ByteCounts{Sent: sent},
+	})
+}
+
+// observeResponse arranges for a response to be reported to the metrics hook, if one is configured, once its body
+// is closed. The body is counted as it is read rather than being buffered.
+func (c *Client) observeResponse(method, routePath string, start time.Time, sent int64, resp *http.Response) {
+
+	if c.metricsHook == nil {
+		return
+	}
+
+	m := RequestMetrics{
+		Method:     method,
+		Route:      routeTemplate(routePath),
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(start),
+		Bytes:      ByteCounts{Sent: sent},
+	}
+
+	resp.Body = &countingBody{ReadCloser: resp.Body, done: func(received int64) {
+		m.Bytes.Received = received
+		c.metricsHook(m)
+	}}
+}
+
+// countingBody counts the bytes read from a response body, calling done with the count when it is closed.
+type countingBody struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(n int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.n) })
+	return err
+}
+
+// routeTemplate replaces any keys within a route with ":key".
+func routeTemplate(routePath string) string {
+
+	parts := strings.Split(routePath, "/")
+	for i := 1; i < len(parts); i++ {
+		if (parts[i-1] == "secret" || parts[i-1] == "private") && parts[i] != "recent" {
+			parts[i] = ":key"
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,31 @@
+package ots
+
+import "net/http"
+
+// RoundTripFunc sends a request and returns its response, in the same way as http.RoundTripper.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of a request, for behaviour which applies to every request such as logging or
+// refreshing credentials. It may modify the request before calling next, inspect the response afterwards, or return
+// without calling next to short-circuit the request. The request's context is available from req.Context().
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware adds middleware which is applied around every request sent by the client, including retries.
+// Middleware is applied outermost first, so the first given sees the request first and the response last. Calling
+// WithMiddleware more than once adds to the chain rather than replacing it.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// roundTrip sends the request through the middleware chain, with the HTTP client at the end of it.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+
+	next := RoundTripFunc(c.httpClient().Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+
+	return next(req)
+}
@@ -0,0 +1,99 @@
+package ots
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewNoop returns a client which makes no network calls, for running code paths which use OTS in tests or local
+// development without a server or credentials. Every operation succeeds with a synthetic Secret. The keys returned
+// are stable for a given sequence of calls, but are not real and cannot be used with an OTS server.
+func NewNoop() *Client {
+	return &Client{
+		Username: "noop",
+		Token:    "noop",
+		hc:       &http.Client{Transport: &noopTransport{}},
+	}
+}
+
+// noopTransport answers requests to the OTS API routes with synthetic responses.
+type noopTransport struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (t *noopTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	form := url.Values{}
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		form, _ = url.ParseQuery(string(b))
+	}
+
+	path := req.URL.Path
+	now := time.Now().Unix()
+
+	var body interface{}
+	switch {
+	case strings.HasSuffix(path, "/status"):
+		body = Health{Status: "nominal"}
+	case strings.HasSuffix(path, "/share"), strings.HasSuffix(path, "/generate"):
+		t.mu.Lock()
+		t.count++
+		n := t.count
+		t.mu.Unlock()
+
+		ttl, _ := strconv.Atoi(form.Get("ttl"))
+		s := Secret{
+			CustomerID:  "noop",
+			MetadataKey: fmt.Sprintf("noopmetadata%d", n),
+			SecretKey:   fmt.Sprintf("noopsecret%d", n),
+			State:       "new",
+			TTL:         ttl,
+			SecretTTL:   ttl,
+			MetadataTTL: ttl * 2,
+			Created:     now,
+			Updated:     now,
+		}
+		if strings.HasSuffix(path, "/generate") {
+			s.Value = "noopvalue"
+		}
+		body = s
+	case strings.Contains(path, "/secret/"):
+		body = Secret{SecretKey: path[strings.LastIndex(path, "/")+1:], Value: "noopvalue"}
+	case strings.HasSuffix(path, "/private/recent"):
+		body = Secrets{}
+	case strings.HasSuffix(path, "/burn"):
+		key := strings.TrimSuffix(path, "/burn")
+		body = Secret{MetadataKey: key[strings.LastIndex(key, "/")+1:], State: "burned", Created: now, Updated: now}
+	case strings.Contains(path, "/private/"):
+		body = Secret{MetadataKey: path[strings.LastIndex(path, "/")+1:], State: "new", Created: now, Updated: now}
+	default:
+		body = messageResponse{Message: "Not found"}
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}, nil
+}
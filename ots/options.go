@@ -0,0 +1,135 @@
+package ots
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Option is used to configure optional behaviour of a Client when it is created with New.
+type Option func(*Client)
+
+// WithCredentials sets the username (email) and API token used to authenticate with OTS.
+func WithCredentials(user, token string) Option {
+	return func(c *Client) {
+		c.Username = user
+		c.Token = token
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request, replacing the default of onetimesecret-go/VERSION.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithBaseURL sets the API base of the OTS server, such as https://ots.internal/api/v1 for a self-hosted instance.
+// Trailing slashes are ignored.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.BaseURL = u
+	}
+}
+
+// RecipientEncoding controls how the recipient is encoded in the form body of a Create or Generate request.
+type RecipientEncoding int
+
+const (
+	// RecipientScalar sends a single 'recipient' form value. This is what the public OTS service
+	// and older self-hosted servers expect, so it is the default.
+	RecipientScalar RecipientEncoding = iota
+
+	// RecipientArray sends the recipients as a repeated 'recipient[]' form value, for newer servers
+	// which accept an array of recipients.
+	RecipientArray
+)
+
+// WithRecipientEncoding sets how recipients are encoded when creating or generating a secret.
+func WithRecipientEncoding(e RecipientEncoding) Option {
+	return func(c *Client) {
+		c.recipientEncoding = e
+	}
+}
+
+// WithAnonymous configures the client to send requests without credentials. OTS allows secrets to be
+// created, generated and retrieved anonymously, but the private metadata endpoints require an account.
+func WithAnonymous() Option {
+	return func(c *Client) {
+		c.anonymous = true
+	}
+}
+
+// WithPassphraseResolver sets a function which is used by Retrieve to look up the passphrase for a secret, for example
+// from a vault, when no passphrase is passed. A passphrase passed explicitly to Retrieve is always used instead of the resolver.
+func WithPassphraseResolver(fn func(ctx context.Context, secretKey string) (string, error)) Option {
+	return func(c *Client) {
+		c.passphraseResolver = fn
+	}
+}
+
+// WithMethodOverride sends every request as a POST, with the intended method in the X-HTTP-Method-Override header.
+// This is needed when the OTS server is behind a gateway which blocks other methods, such as GET, and the server
+// or gateway understands the override header. It is disabled by default.
+func WithMethodOverride() Option {
+	return func(c *Client) {
+		c.methodOverride = true
+	}
+}
+
+// WithResponseValidator sets a function which is called with the route, such as "share", and the parsed response of
+// every request which returns a Secret. If it returns an error, that error is returned to the caller instead of the
+// response. This allows your own expectations to be enforced, such as a MetadataKey always being present on create.
+func WithResponseValidator(fn func(route string, s *Secret) error) Option {
+	return func(c *Client) {
+		c.responseValidator = fn
+	}
+}
+
+// WithFallbackBaseURLs sets API bases, such as https://ots-backup.internal/api/v1, which are tried in order when a
+// request cannot be sent to the previous base. A response from a server, including an error status, is never retried
+// against another base. Create, Generate and Retrieve only move on to the next base when a connection could not be
+// made, so that a request which timed out after the server received it does not create a duplicate secret, or lose
+// the value of a secret which the server has already consumed.
+func WithFallbackBaseURLs(urls ...string) Option {
+	return func(c *Client) {
+		for _, u := range urls {
+			c.fallbackBases = append(c.fallbackBases, strings.TrimRight(u, "/"))
+		}
+	}
+}
+
+// WithRecentPassphraseEnrichment makes RetrieveRecentMetadata fetch the metadata of each secret individually to fill
+// in PassphraseRequired, for servers which do not include it in the recent metadata response. This sends an extra
+// request per secret, so it is disabled by default.
+func WithRecentPassphraseEnrichment() Option {
+	return func(c *Client) {
+		c.enrichRecentPassphrase = true
+	}
+}
+
+// WithForbidRecipients enforces link-only sharing, for policies which do not allow secrets to be emailed by OTS.
+// Create and Generate return ErrRecipientForbidden, without sending a request, when a recipient is given.
+func WithForbidRecipients() Option {
+	return func(c *Client) {
+		c.forbidRecipients = true
+	}
+}
+
+// WithUntrustedURLs allows RetrieveAny to accept share URLs for any host, rather than only the hosts of the client's
+// base URLs. The secret is always retrieved from the client's base URL, whatever host the share URL is for.
+func WithUntrustedURLs() Option {
+	return func(c *Client) {
+		c.allowUntrustedURLs = true
+	}
+}
+
+// WithOnRetry sets a function which is called before a request is retried, for logging and metrics. The attempt is
+// the number of the attempt about to be made, starting at 2 for the first retry, err is why the previous attempt
+// failed and delay is how long the client will wait before retrying. It is not called for the first attempt.
+// Retrying against the next base from WithFallbackBaseURLs happens without a delay.
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(c *Client) {
+		c.onRetry = fn
+	}
+}
@@ -0,0 +1,100 @@
+package ots
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecipientEncoding(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		encoding RecipientEncoding
+		key      string
+		absent   string
+	}{
+		{name: "scalar", encoding: RecipientScalar, key: "recipient", absent: "recipient[]"},
+		{name: "array", encoding: RecipientArray, key: "recipient[]", absent: "recipient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+			c := ts.client(WithRecipientEncoding(tt.encoding))
+
+			_, err := c.CreateWithOptions(context.Background(), CreateOptions{
+				Secret:     "hunter2",
+				Recipient:  "alice@example.com",
+				Recipients: []string{"bob@example.com"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			form := ts.last(t).Form
+			if want := []string{"alice@example.com", "bob@example.com"}; !reflect.DeepEqual(form[tt.key], want) {
+				t.Errorf("create %s = %q, want %q", tt.key, form[tt.key], want)
+			}
+			if _, ok := form[tt.absent]; ok {
+				t.Errorf("create sent %s, want only %s", tt.absent, tt.key)
+			}
+
+			if _, err := c.Generate("alice@example.com", "", 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			form = ts.last(t).Form
+			if want := []string{"alice@example.com"}; !reflect.DeepEqual(form[tt.key], want) {
+				t.Errorf("generate %s = %q, want %q", tt.key, form[tt.key], want)
+			}
+			if _, ok := form[tt.absent]; ok {
+				t.Errorf("generate sent %s, want only %s", tt.absent, tt.key)
+			}
+		})
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	c := NewClient(
+		WithCredentials("user@example.com", "token"),
+		WithBaseURL(ts.URL+"/"),
+		WithUserAgent("my-service/1.0"),
+		WithTimeout(5*time.Second),
+	)
+
+	if err := c.Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := ts.last(t)
+	if ua := req.Header.Get("User-Agent"); ua != "my-service/1.0" {
+		t.Errorf("User-Agent = %q, want my-service/1.0", ua)
+	}
+	user, token, _ := (&http.Request{Header: req.Header}).BasicAuth()
+	if user != "user@example.com" || token != "token" {
+		t.Errorf("basic auth = %q, %q, want the credentials from WithCredentials", user, token)
+	}
+	if c.hc.Timeout != 5*time.Second {
+		t.Errorf("timeout = %s, want 5s", c.hc.Timeout)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	if err := ts.client().Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ua := ts.last(t).Header.Get("User-Agent"); !strings.HasPrefix(ua, "onetimesecret-go/") {
+		t.Errorf("User-Agent = %q, want onetimesecret-go/VERSION", ua)
+	}
+}
@@ -1,21 +1,36 @@
 // Package ots provides a client for interacting with the OneTimeSecret API.
+//
+// Each method which sends a request has a Context variant, such as CreateContext for Create, which binds the request
+// to a context.Context. Cancelling the context aborts the request, and the returned error matches ctx.Err() when
+// checked with errors.Is.
+//
+// Every time.Time returned by the package, such as from CreatedTime or in SecretExpiresAt, is in UTC, so that times
+// compare and log consistently regardless of the local time zone.
 package ots
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
 	base = "https://onetimesecret.com/api/v1"
+
+	passphraseChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 )
 
 // Client is used to set the user's 'Username' and 'Token' for interaction with the OneTimeSecret API.
@@ -25,59 +40,177 @@ type Client struct {
 	Username string
 
 	// API token from the OTS website
-	Token    string
+	Token string
+
+	// The API base of the OTS server, such as https://ots.internal/api/v1 for a self-hosted instance.
+	// When empty, the public OTS service at https://onetimesecret.com/api/v1 is used.
+	BaseURL string
+
+	credMu sync.RWMutex
+
+	recipientEncoding RecipientEncoding
+	anonymous         bool
+	traceFn           func(TraceInfo)
+
+	passphraseResolver func(ctx context.Context, secretKey string) (string, error)
+	responseValidator  func(route string, s *Secret) error
+	auditLog           *auditLog
+	auditErrorHandler  func(err error)
+	statusCache        *statusCache
+	methodOverride     bool
+	fallbackBases      []string
+	apiVersion         APIVersion
+	maxSecretSize      int
+	maxTTL             time.Duration
+	truncateOversized  bool
+	events             chan Event
+
+	enrichRecentPassphrase bool
+	useNumber              bool
+	errorRoutes            ErrorRoutes
+	ttlStats               *ttlStats
+	tombstones             *tombstones
+	metricsHook            func(RequestMetrics)
+	logger                 Logger
+	requestHook            func(req *http.Request)
+	responseHook           func(resp *http.Response, d time.Duration)
+	forbidRecipients       bool
+	allowUntrustedURLs     bool
+	onRetry                func(attempt int, err error, delay time.Duration)
+	maxRetries             int
+	retryBackoff           time.Duration
+	retryPredicate         func(resp *http.Response, err error) bool
+	middleware             []Middleware
+
+	userAgent           string
+	hc                  *http.Client
+	timeout             time.Duration
+	timeoutSet          bool
+	dialTimeout         time.Duration
+	tlsHandshakeTimeout time.Duration
 }
 
 // Secret is a struct which contains the expected fields from the /share API endpoint.
 type Secret struct {
 
 	// This is your ID for your account.
-	CustomerID         string   `json:"custid,omitempty"`
+	CustomerID string `json:"custid,omitempty"`
 
 	// This should NOT be shared, it is the unique key to retrieve metadata about the secret.
-	MetadataKey        string   `json:"metadata_key,omitempty"`
+	MetadataKey string `json:"metadata_key,omitempty"`
 
 	// The key for the secret you create, you can share this value.
-	SecretKey          string   `json:"secret_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
 
 	// When retrieving a secret, this value will be populated.
-	Value              string   `json:"value,omitempty"`
+	Value string `json:"value,omitempty"`
+
+	// The path of the secret's share link, such as /secret/SECRET_KEY, for servers which report it. This is relative
+	// to the web UI base and is used by SecretURL, as some servers use a different path. Empty if not reported.
+	SharePath string `json:"share_path,omitempty"`
 
-	// A secret may be viewed or burned. 
-	State              string   `json:"state,omitempty"`
+	// A secret may be viewed or burned.
+	State string `json:"state,omitempty"`
 
-	// This represents a slice of email addresses who have received the secret, it is obfuscated. 
-	Recipient          []string `json:"recipient,omitempty"`
+	// This represents a slice of email addresses who have received the secret, it is obfuscated.
+	Recipient []string `json:"recipient,omitempty"`
 
 	// Time to live in seconds, this is not the remaining time. It is what you specified on creation.
-	TTL                int      `json:"ttl,omitempty"`
+	TTL int `json:"ttl,omitempty"`
 
 	// Remaining time, in seconds, the metadata for a secret is valid for before being destroyed.
-	MetadataTTL        int      `json:"metadata_ttl,omitempty"`
+	MetadataTTL int `json:"metadata_ttl,omitempty"`
 
 	// Remaining time, in seconds, the secret is valid for before being destroyed.
-	SecretTTL          int      `json:"secret_ttl,omitempty"`
+	SecretTTL int `json:"secret_ttl,omitempty"`
 
 	// Timestamp of when the secret was created, this is in unix time.
-	Created            int64    `json:"created,omitempty"`
+	Created int64 `json:"created,omitempty"`
 
 	// Timestamp of when the secret was last updated, this is in unix time.
-	Updated            int64    `json:"updated,omitempty"`
+	Updated int64 `json:"updated,omitempty"`
+
+	// Timestamp of when the recipient viewed the secret, this is in unix time. Zero if it has not been viewed.
+	Received int64 `json:"received,omitempty"`
 
 	// Whether the secret requires a passphrase or not.
-	PassphraseRequired bool     `json:"passphrase_required,omitempty"`
+	PassphraseRequired bool `json:"passphrase_required,omitempty"`
+
+	// A non-sensitive reminder of which passphrase to use, such as "your employee ID", for servers which support it.
+	// This is empty when the server does not provide one.
+	PassphraseHint string `json:"passphrase_hint,omitempty"`
+
+	// Whether the server reports that the value was stored encrypted, this is false when the server does not say.
+	ValueEncrypted bool `json:"value_encrypted,omitempty"`
+
+	// When the secret expires, calculated from SecretTTL and the time reported by the server. This is zero
+	// when the response did not include a SecretTTL.
+	SecretExpiresAt time.Time `json:"-"`
+
+	// When the metadata expires, calculated from MetadataTTL and the time reported by the server. This is zero
+	// when the response did not include a MetadataTTL.
+	MetadataExpiresAt time.Time `json:"-"`
+
+	// The recipient's challenge, if one was given in CreateOptions. This is kept by the client and never sent to OTS.
+	Challenge *ChallengeRecord `json:"-"`
+
+	// Whether the value was cut short before it was sent, because it was larger than the size set by
+	// WithMaxSecretSize and the client uses WithTruncateOversized. This is set by the client, not the server.
+	Truncated bool `json:"-"`
+}
+
+// CreateOptions are the parameters used to create a secret with CreateWithOptions.
+type CreateOptions struct {
+
+	// The value that you wish to store.
+	Secret string
+
+	// The string with which the recipient is allowed to view the secret.
+	Passphrase string
+
+	// Who you wish to send the secret to, using their email address.
+	Recipient string
+
+	// Further recipients to send the secret to, in addition to Recipient. Each is sent as a repeated recipient value,
+	// or in recipient[] when the client uses RecipientArray.
+	Recipients []string
+
+	// The time-to-live of the secret, in seconds.
+	TTL int
+
+	// A question for the recipient to answer before the passphrase is given to them. This is not sent to OTS,
+	// it is kept on the returned Secret and in its TrackingRecord. See Challenge.
+	Challenge *Challenge
+
+	// How many times the secret can be read before it is burned. Only some OTS deployments support secrets which
+	// can be read more than once, other servers ignore it. Zero omits the parameter, which means a single read.
+	ReadLimit int
+
+	// An absolute https URL which the server notifies when the secret is viewed or expires, sent as callback_url.
+	// This is only supported by some OTS deployments, other servers ignore it, so the secret is still created
+	// but no notification is sent. Empty omits the parameter.
+	CallbackURL string
 }
 
 // Secrets is a wrapper type for a slice of Secret
 type Secrets []Secret
 
+// messageResponse is the body OTS returns when a request fails, such as {"message": "Unknown secret"}.
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
 // Health is a simple struct for verifying the response from the /status endpoint.
 type Health struct {
 	Status string
+
+	// Why the server is not nominal, if it says. Servers may report this as either a reason or a message.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 // PrettyPrint is a simple wrapper for printing out the Secret struct data
-// in a nicer format.
+// in a nicer format. This includes the Value, so use String when the output may be logged.
 func (s *Secret) PrettyPrint() error {
 
 	prettyJSON, err := json.MarshalIndent(s, "", "\t")
@@ -90,47 +223,115 @@ func (s *Secret) PrettyPrint() error {
 }
 
 // New returns a populated client to OneTimeSecret, this uses your provided username (email) and token (API token in your account)
-// in order to authenticate to the API server with OTS.
-func New(user, token string) *Client {
-	return &Client{Username: user, Token: token}
+// in order to authenticate to the API server with OTS. Any options are applied to the client in the order given.
+func New(user, token string, opts ...Option) *Client {
+	c := &Client{Username: user, Token: token}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.configureTransport()
+	return c
+}
+
+// NewClient returns a client configured entirely by options, such as WithCredentials and WithBaseURL. A client
+// without credentials can only be used for anonymous requests, see WithAnonymous.
+func NewClient(opts ...Option) *Client {
+	return New("", "", opts...)
+}
+
+// NewWithURL is the same as New, but requests are sent to the OTS server at baseURL, such as https://ots.internal/api/v1.
+func NewWithURL(user, token, baseURL string, opts ...Option) *Client {
+	return New(user, token, append([]Option{WithBaseURL(baseURL)}, opts...)...)
 }
 
 // Status will check the current status of the OTS system.
 // This returns an error if the OTS servers are offline or there are other problems with the request.
+// If the client was created with WithStatusCache, a cached result may be returned.
 func (c *Client) Status() error {
+	return c.StatusContext(context.Background())
+}
 
-	endpoint := createURI("status")
+// StatusContext is the same as Status, but the request is bound to ctx.
+func (c *Client) StatusContext(ctx context.Context) error {
 
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		log.Println("GET: unable to create new request.")
+	if ok, err := c.cachedStatus(); ok {
 		return err
 	}
-	req.SetBasicAuth(c.Username, c.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	return c.RefreshStatusContext(ctx)
+}
+
+// StatusInfo is the status reported by the OTS server, as returned by Client.StatusInfo.
+type StatusInfo struct {
+
+	// The status, such as "nominal", "offline" or "unavailable".
+	Status string
+
+	// Why the server is not nominal, if it says.
+	Reason string
+}
+
+// StatusInfo checks the current status of the OTS system and returns what the server reported, so that callers can
+// see the status explicitly. Unlike Status, a server which reports that it is offline is not an error; an error is
+// only returned when the status could not be determined. The result is never cached by WithStatusCache.
+func (c *Client) StatusInfo(ctx context.Context) (*StatusInfo, error) {
+
+	ctx = withRequestID(ctx)
+
+	resp, err := c.do(ctx, "GET", "status", nil)
 	if err != nil {
-		log.Println("GET: unable to send request.")
-		return err
+		c.debug(ctx, "unable to send request", "method", "GET", "route", "status", "error", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Println("GET: unable to read response.")
-		return err
+		c.debug(ctx, "unable to read response", "method", "GET", "route", "status", "error", err)
+		return nil, c.requestError(ctx, "GET", "status", err)
 	}
 
+	// An offline server may respond with an error status, so the health is checked before the status code.
 	var h *Health
+	jsonErr := json.Unmarshal(body, &h)
+
+	if jsonErr != nil || h == nil || h.Status != "offline" {
+		if err := checkResponse(resp.StatusCode, body); err != nil {
+			return nil, c.requestError(ctx, "GET", "status", err)
+		}
+	}
+
+	if jsonErr != nil {
+		c.debug(ctx, "unable to decode response", "method", "GET", "route", "status", "error", jsonErr)
+		return nil, c.requestError(ctx, "GET", "status", jsonErr)
+	}
+
+	info := &StatusInfo{}
+	if h != nil {
+		info.Status = h.Status
+		info.Reason = h.Reason
+		if info.Reason == "" {
+			info.Reason = h.Message
+		}
+	}
+
+	return info, nil
+}
 
-	err = json.Unmarshal(body, &h)
+// status checks the current status of the OTS system, returning an *OfflineError if the server is offline.
+func (c *Client) status(ctx context.Context) error {
+
+	info, err := c.StatusInfo(ctx)
 	if err != nil {
-		log.Println("GET: unable to unmarshal response.")
 		return err
 	}
 
-	if h.Status == "offline" {
-		return errors.New("server is offline, try again later")
+	if info.Status == "offline" {
+		reason := info.Reason
+		if reason == "" {
+			reason = "server is offline, try again later"
+		}
+		return &OfflineError{Reason: reason}
 	}
 
 	return nil
@@ -141,43 +342,209 @@ func (c *Client) Status() error {
 // Passphrase is the string with which the recipient is allowed to view the secret.
 // Recipient is who you wish to send the secret to, using their email address.
 // TTL is the time-to-live of the secret, in seconds. Once this expires, the secret is deleted.
+// A TTL of zero uses the TTL from ContextWithTTL, or the server's default if there is none. A negative TTL, or one
+// longer than the maximum set by WithMaxTTL, returns ErrInvalidTTL without sending a request.
+// An empty passphrase or recipient is left out of the request, rather than being sent as an empty value, so the
+// secret has no passphrase or is not emailed.
 // This request is sent via POST https://onetimesecret.com/api/v1/share
 func (c *Client) Create(secret, passphrase, recipient string, ttl int) (*Secret, error) {
+	return c.CreateContext(context.Background(), secret, passphrase, recipient, ttl)
+}
+
+// CreateContext is the same as Create, but the request is bound to ctx. If ttl is 0, the TTL set on ctx by
+// ContextWithTTL is used instead.
+func (c *Client) CreateContext(ctx context.Context, secret, passphrase, recipient string, ttl int) (*Secret, error) {
+	return c.CreateWithOptions(ctx, CreateOptions{
+		Secret:     secret,
+		Passphrase: passphrase,
+		Recipient:  recipient,
+		TTL:        ttl,
+	})
+}
+
+// CreateWithOptions is the same as CreateContext, but takes a CreateOptions which allows further parameters to be set.
+func (c *Client) CreateWithOptions(ctx context.Context, opts CreateOptions) (*Secret, error) {
 
 	route := "share"
 
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = ttlFromContext(ctx)
+	}
+
+	if err := c.checkTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	recipients := opts.recipients()
+
+	if c.forbidRecipients && len(recipients) > 0 {
+		return nil, ErrRecipientForbidden
+	}
+
+	if err := checkRecipients(recipients); err != nil {
+		return nil, err
+	}
+
+	if opts.ReadLimit < 0 {
+		return nil, fmt.Errorf("ots: read limit must be positive, got %d", opts.ReadLimit)
+	}
+
+	if opts.CallbackURL != "" {
+		if u, err := url.Parse(opts.CallbackURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("ots: callback URL %q must be an absolute https URL", opts.CallbackURL)
+		}
+	}
+
+	secret, truncated, err := c.checkSize(ctx, opts.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge *ChallengeRecord
+	if opts.Challenge != nil {
+		if challenge, err = newChallengeRecord(opts.Challenge); err != nil {
+			return nil, err
+		}
+	}
+
 	v := url.Values{}
 	v.Set("secret", secret)
-	v.Set("passphrase", passphrase)
-	v.Set("ttl", strconv.Itoa(ttl))
-	v.Set("recipient", recipient)
+	if opts.Passphrase != "" {
+		v.Set("passphrase", opts.Passphrase)
+	}
+	if ttl > 0 {
+		v.Set("ttl", strconv.Itoa(ttl))
+	}
+	for _, r := range recipients {
+		c.setRecipient(v, r)
+	}
+
+	if opts.ReadLimit > 0 {
+		v.Set("read_limit", strconv.Itoa(opts.ReadLimit))
+	}
 
-	resp, err := c.postRequest(route, strings.NewReader(v.Encode()))
+	if opts.CallbackURL != "" {
+		v.Set("callback_url", opts.CallbackURL)
+	}
+
+	resp, err := c.postRequest(ctx, route, strings.NewReader(v.Encode()))
 	if err != nil {
 		return nil, err
 	}
 
+	if len(resp.Recipient) == 0 && len(recipients) > 0 {
+		resp.Recipient = recipients
+	}
+
+	resp.Challenge = challenge
+	resp.Truncated = truncated
+
+	c.audit("create", resp.MetadataKey, recipients, ttl)
+	c.recordTTL(ttl)
+	c.publish(EventCreated, resp)
+
 	return resp, nil
 
 }
 
+// Share creates a secret without a recipient and returns the link to send to whoever should view it, for the
+// common case of sharing some text. It is the same as calling Create and then SecretURL.
+func (c *Client) Share(secret, passphrase string, ttl int) (string, error) {
+
+	s, err := c.Create(secret, passphrase, "", ttl)
+	if err != nil {
+		return "", err
+	}
+
+	return c.SecretURL(s), nil
+}
+
+// CreateWithGeneratedPassphrase is the same as Create, but a cryptographically random passphrase of passphraseLen
+// characters is generated and used for the secret. The passphrase is returned alongside the secret so that it
+// can be communicated to the recipient separately.
+func (c *Client) CreateWithGeneratedPassphrase(secret, recipient string, ttl int, passphraseLen int) (*Secret, string, error) {
+
+	passphrase, err := generatePassphrase(passphraseLen)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.Create(secret, passphrase, recipient, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp, passphrase, nil
+
+}
+
 // Generate will return a short, unique secret which is useful for temporary passwords, one-time pads, salts etc.
 // The response value is the same format as Create(), but the Value field is populated.
 // This request is sent via POST https://onetimesecret.com/api/v1/generate
 func (c *Client) Generate(recipient, passphrase string, ttl int) (*Secret, error) {
+	return c.GenerateContext(context.Background(), recipient, passphrase, ttl)
+}
+
+// GeneratePassword generates a random value with Generate, without a recipient or passphrase, and returns the value
+// and the key of its secret directly. This is the common case of making a random password which can also be shared
+// using the secret key.
+func (c *Client) GeneratePassword(ttl int) (value, secretKey string, err error) {
+
+	s, err := c.Generate("", "", ttl)
+	if err != nil {
+		return "", "", err
+	}
+
+	return s.Value, s.SecretKey, nil
+}
+
+// GenerateContext is the same as Generate, but the request is bound to ctx. If ttl is 0, the TTL set on ctx by
+// ContextWithTTL is used instead, or the server's default if there is none. The TTL is checked in the same way as
+// for Create.
+func (c *Client) GenerateContext(ctx context.Context, recipient, passphrase string, ttl int) (*Secret, error) {
 
 	route := "generate"
 
+	if ttl == 0 {
+		ttl = ttlFromContext(ctx)
+	}
+
+	if err := c.checkTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	if c.forbidRecipients && recipient != "" {
+		return nil, ErrRecipientForbidden
+	}
+
+	if err := checkRecipients(recipientList(recipient)); err != nil {
+		return nil, err
+	}
+
 	v := url.Values{}
-	v.Set("passphrase", passphrase)
-	v.Set("ttl", strconv.Itoa(ttl))
-	v.Set("recipient", recipient)
+	if passphrase != "" {
+		v.Set("passphrase", passphrase)
+	}
+	if ttl > 0 {
+		v.Set("ttl", strconv.Itoa(ttl))
+	}
+	if recipient != "" {
+		c.setRecipient(v, recipient)
+	}
 
-	resp, err := c.postRequest(route, strings.NewReader(v.Encode()))
+	resp, err := c.postRequest(ctx, route, strings.NewReader(v.Encode()))
 	if err != nil {
 		return nil, err
 	}
 
+	if len(resp.Recipient) == 0 && recipient != "" {
+		resp.Recipient = []string{recipient}
+	}
+
+	c.audit("generate", resp.MetadataKey, recipientList(recipient), ttl)
+	c.publish(EventGenerated, resp)
+
 	return resp, nil
 
 }
@@ -185,8 +552,26 @@ func (c *Client) Generate(recipient, passphrase string, ttl int) (*Secret, error
 // Retrieve is used to get the value of a secret which was previously stored. Once you retrieve the secret, it is no longer available.
 // The secretKey parameter is gained from the response when initially creating a secret that is to be shared and the passphrase is what was
 // specified upon creation of the said secret.
+// If passphrase is empty and the client has a resolver set by WithPassphraseResolver, the resolver is used to look it up.
+// An explicit passphrase always takes precedence over the resolver.
+// The value is in the Value field of the returned secret, also available from Plaintext. If the secret has already
+// been viewed, burned or has expired, the error matches ErrSecretNotFound. A wrong or missing passphrase returns an
+// error matching ErrWrongPassphrase or ErrPassphraseRequired instead, so the two cases can be told apart.
 // This request is sent via POST https://onetimesecret.com/api/v1/secret/SECRET_KEY
 func (c *Client) Retrieve(secretKey, passphrase string) (*Secret, error) {
+	return c.RetrieveContext(context.Background(), secretKey, passphrase)
+}
+
+// RetrieveContext is the same as Retrieve, but the request is bound to ctx.
+func (c *Client) RetrieveContext(ctx context.Context, secretKey, passphrase string) (*Secret, error) {
+
+	if passphrase == "" && c.passphraseResolver != nil {
+		p, err := c.passphraseResolver(ctx, secretKey)
+		if err != nil {
+			return nil, fmt.Errorf("resolving passphrase: %w", err)
+		}
+		passphrase = p
+	}
 
 	route := fmt.Sprintf("secret/%s", secretKey)
 
@@ -195,23 +580,73 @@ func (c *Client) Retrieve(secretKey, passphrase string) (*Secret, error) {
 	v.Set("secret_key", secretKey)
 	v.Set("passphrase", passphrase)
 
-	resp, err := c.postRequest(route, strings.NewReader(v.Encode()))
+	resp, err := c.postRequest(ctx, route, strings.NewReader(v.Encode()))
 	if err != nil {
-		return nil, err
+		return nil, retrieveError(err, passphrase)
+	}
+
+	if resp == nil || resp.Value == "" {
+		return nil, &secretNotFoundError{err: c.requestError(ctx, "POST", route, ErrEmptySecret)}
 	}
 
 	return resp, nil
 
 }
 
+// RetrieveAny is the same as Retrieve, but accepts either a bare secret key or a full share URL, whichever the
+// recipient was given, using ParseSecretKey. This makes it a convenient entry point for CLI tools where users paste
+// whatever they received. ErrInvalidSecretKey is returned if the input is neither.
+//
+// A share URL for a different host than the client's base URLs is refused with ErrUntrustedURL, see IsTrustedURL,
+// unless the client was created with WithUntrustedURLs.
+func (c *Client) RetrieveAny(ctx context.Context, keyOrURL, passphrase string) (*Secret, error) {
+
+	secretKey, err := ParseSecretKey(keyOrURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.allowUntrustedURLs && secretKey != strings.TrimSpace(keyOrURL) && !c.IsTrustedURL(keyOrURL) {
+		return nil, fmt.Errorf("%w: %q", ErrUntrustedURL, keyOrURL)
+	}
+
+	return c.RetrieveContext(ctx, secretKey, passphrase)
+}
+
+// RetrieveWithCandidates retrieves a secret when the passphrase is one of several candidates, returning the secret
+// and the passphrase which worked. As a failed attempt may consume the secret, each candidate would need to be checked
+// without reading it first. The OTS API has no way to do this, so ErrPreValidationUnsupported is returned without
+// sending any request when there is more than one candidate, rather than risking the secret. With a single candidate
+// this is the same as Retrieve.
+func (c *Client) RetrieveWithCandidates(ctx context.Context, secretKey string, passphrases []string) (*Secret, string, error) {
+
+	switch len(passphrases) {
+	case 0:
+		return nil, "", errors.New("ots: no candidate passphrases given")
+	case 1:
+		s, err := c.RetrieveContext(ctx, secretKey, passphrases[0])
+		if err != nil {
+			return nil, "", err
+		}
+		return s, passphrases[0], nil
+	default:
+		return nil, "", ErrPreValidationUnsupported
+	}
+}
+
 // RetrieveMetadata is used to safely get the associated metadata for particular key. This is intended for the owner of the secret
 // and should be kept private, this lets you view basic information about the secret, such as when or if it has been viewed.
 // This request is sent via POST https://onetimesecret.com/api/v1/private/METADATA_KEY
 func (c *Client) RetrieveMetadata(metadataKey string) (*Secret, error) {
+	return c.RetrieveMetadataContext(context.Background(), metadataKey)
+}
+
+// RetrieveMetadataContext is the same as RetrieveMetadata, but the request is bound to ctx.
+func (c *Client) RetrieveMetadataContext(ctx context.Context, metadataKey string) (*Secret, error) {
 
 	route := fmt.Sprintf("private/%s", metadataKey)
 
-	resp, err := c.postRequest(route, nil)
+	resp, err := c.postRequest(ctx, route, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -221,89 +656,405 @@ func (c *Client) RetrieveMetadata(metadataKey string) (*Secret, error) {
 }
 
 // Burn will remove a secret, stopping it from being read by the recipient.
+// Only the metadata key is sent, a burn request never includes a secret or passphrase.
+// The returned secret is the metadata from the burn response, so its State is "burned".
 // This request is sent via POST https://onetimesecret.com/api/v1/private/METADATA_KEY/burn
 func (c *Client) Burn(metadataKey string) (*Secret, error) {
+	return c.BurnContext(context.Background(), metadataKey)
+}
+
+// BurnContext is the same as Burn, but the request is bound to ctx.
+func (c *Client) BurnContext(ctx context.Context, metadataKey string) (*Secret, error) {
 
 	route := fmt.Sprintf("private/%s/burn", metadataKey)
 
-	resp, err := c.postRequest(route, nil)
+	resp, err := c.postRequest(ctx, route, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	c.audit("burn", metadataKey, nil, 0)
+	c.recordTombstone(metadataKey)
+	c.publish(EventBurned, resp)
+
 	return resp, nil
 
 }
 
 // RetrieveRecentMetadata is used to get a list of metadata for secrets that have not yet been viewed by the recipient.
+// PassphraseRequired is parsed when the server includes it, but not every server does for this endpoint. If the client
+// was created with WithRecentPassphraseEnrichment, the metadata of each secret not marked as requiring a passphrase
+// is fetched individually to fill it in, at the cost of an extra request per secret.
+// The metadata has no Value. When there are no recent secrets, an empty, non-nil Secrets is returned.
 // This request is sent via GET https://onetimesecret.com/api/v1/private/recent
 func (c *Client) RetrieveRecentMetadata() (*Secrets, error) {
+	return c.RetrieveRecentMetadataContext(context.Background())
+}
 
-	endpoint := createURI("private/recent")
+// RetrieveRecentMetadataContext is the same as RetrieveRecentMetadata, but the request is bound to ctx.
+func (c *Client) RetrieveRecentMetadataContext(ctx context.Context) (*Secrets, error) {
 
-	req, err := http.NewRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.SetBasicAuth(c.Username, c.Token)
+	ctx = withRequestID(ctx)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(ctx, "GET", "private/recent", nil)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	bodyText, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, c.requestError(ctx, "GET", "private/recent", err)
+	}
+
+	if err := checkResponse(resp.StatusCode, bodyText); err != nil {
+		return nil, c.requestError(ctx, "GET", "private/recent", err)
 	}
 
 	var otsResponse *Secrets
 
-	err = json.Unmarshal(bodyText, &otsResponse)
+	err = c.decode(c.unwrapRecord(bodyText), &otsResponse)
 	if err != nil {
-		return nil, err
+		return nil, c.requestError(ctx, "GET", "private/recent", err)
+	}
+
+	if otsResponse == nil || *otsResponse == nil {
+		otsResponse = &Secrets{}
+	}
+
+	now := serverTime(resp.Header)
+	for i := range *otsResponse {
+		(*otsResponse)[i].setExpiry(now)
+	}
+
+	if c.enrichRecentPassphrase {
+		for i, secret := range *otsResponse {
+			if secret.PassphraseRequired || secret.MetadataKey == "" {
+				continue
+			}
+			m, err := c.RetrieveMetadataContext(ctx, secret.MetadataKey)
+			if err != nil {
+				return nil, err
+			}
+			(*otsResponse)[i].PassphraseRequired = m.PassphraseRequired
+		}
 	}
 
 	return otsResponse, nil
 }
 
-func (c *Client) postRequest(routePath string, body io.Reader) (*Secret, error) {
+// setRecipient adds the recipient to the form values, using the client's configured RecipientEncoding.
+func (c *Client) setRecipient(v url.Values, recipient string) {
+	switch c.recipientEncoding {
+	case RecipientArray:
+		v.Add("recipient[]", recipient)
+	default:
+		v.Add("recipient", recipient)
+	}
+}
 
-	endpoint := createURI(routePath)
+// recipients returns Recipient followed by Recipients, leaving out any which are empty.
+func (opts CreateOptions) recipients() []string {
 
-	req, err := http.NewRequest("POST", endpoint, body)
-	if err != nil {
-		log.Println("POST: Unable to create new request.")
-		return nil, err
+	var all []string
+	for _, r := range append([]string{opts.Recipient}, opts.Recipients...) {
+		if r != "" {
+			all = append(all, r)
+		}
+	}
+
+	return all
+}
+
+// recipientList returns recipient as a list, which is empty if there is no recipient.
+func recipientList(recipient string) []string {
+	if recipient == "" {
+		return nil
+	}
+	return []string{recipient}
+}
+
+// setAuth adds the client's credentials to the request, an error is returned if they are empty
+// unless the client is anonymous.
+func (c *Client) setAuth(req *http.Request) error {
+	if c.anonymous {
+		return nil
 	}
 
-	req.SetBasicAuth(c.Username, c.Token)
+	user, token := c.credentials()
+	if user == "" || token == "" {
+		return ErrMissingCredentials
+	}
+
+	req.SetBasicAuth(user, token)
+	return nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// newRequest creates a request for the endpoint. If the client was created with WithMethodOverride, the request is
+// sent as a POST with the intended method in the X-HTTP-Method-Override header.
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+
+	if !c.methodOverride || method == "POST" {
+		return http.NewRequestWithContext(ctx, method, endpoint, body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
 	if err != nil {
-		log.Println("POST: Unable to send request.")
 		return nil, err
 	}
+	req.Header.Set("X-HTTP-Method-Override", method)
 
-	responseBody, err := ioutil.ReadAll(resp.Body)
+	return req, nil
+}
+
+// do sends a request to the route, trying each fallback base in turn if the request cannot be sent to the previous one.
+// Each base is attempted at most once per try. If the client was created with WithMaxRetries, transient failures are
+// retried after a delay, see WithMaxRetries for which failures are retried.
+func (c *Client) do(ctx context.Context, method, routePath string, body io.Reader) (*http.Response, error) {
+
+	ctx = withRequestID(ctx)
+
+	var payload []byte
+	if body != nil {
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = b
+	}
+
+	if isBurnRoute(routePath) && len(payload) > 0 {
+		payload = stripSecretFields(payload)
+	}
+
+	attempt := 0
+	for retry := 1; ; retry++ {
+
+		sent := attempt
+		resp, err := c.sendToBases(ctx, method, routePath, payload, body != nil, &attempt)
+		if ctx.Err() != nil {
+			discard(resp)
+			return nil, c.requestError(ctx, method, routePath, ctx.Err())
+		}
+
+		delay := c.retryDelay(retry)
+		if retry > c.maxRetries || attempt == sent || !c.retryable(routePath, resp, err) || pastDeadline(ctx, delay) {
+			if err != nil {
+				return nil, c.requestError(ctx, method, routePath, err)
+			}
+			return resp, nil
+		}
+		discard(resp)
+
+		if c.onRetry != nil {
+			c.onRetry(attempt+1, retryError(resp, err), delay)
+		}
+
+		if !sleep(ctx, delay) {
+			return nil, c.requestError(ctx, method, routePath, ctx.Err())
+		}
+	}
+}
+
+// sendToBases sends a request to the route at each base in turn, until one can be sent. The attempt is incremented
+// for each request sent, so it is unchanged if the request could not be built. A request which creates or retrieves
+// a secret only moves on to the next base when a connection could not be made, as any other failure, such as a
+// timeout, could happen after the server has received it and created or consumed the secret.
+func (c *Client) sendToBases(ctx context.Context, method, routePath string, payload []byte, hasBody bool, attempt *int) (*http.Response, error) {
+
+	var lastErr error
+	for i, baseURL := range append([]string{c.baseURL()}, c.fallbackBases...) {
+
+		if i > 0 && c.onRetry != nil {
+			c.onRetry(*attempt+1, lastErr, 0)
+		}
+
+		var reqBody io.Reader
+		if hasBody {
+			reqBody = bytes.NewReader(payload)
+		}
+
+		req, err := c.newRequest(ctx, method, createURI(c.versionedBase(baseURL), routePath), reqBody)
+		if err != nil {
+			c.debug(ctx, "unable to create request", "method", method, "route", maskRoute(routePath), "error", err)
+			return nil, err
+		}
+
+		if err := c.setAuth(req); err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Request-ID", RequestIDFromContext(ctx))
+		req.Header.Set("User-Agent", c.userAgentHeader())
+
+		*attempt++
+		c.hookRequest(req)
+		start := time.Now()
+		resp, err := c.send(req)
+		if err == nil {
+			c.hookResponse(resp, time.Since(start))
+			c.observeResponse(method, routePath, start, int64(len(payload)), resp)
+			return resp, nil
+		}
+		c.observe(method, routePath, start, int64(len(payload)), err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if isUnsafeToResend(routePath) && !isConnectionError(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// send performs the HTTP request through any middleware, tracing it if the client has been configured with WithHTTPTrace.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+
+	if c.traceFn == nil {
+		return c.roundTrip(req)
+	}
+
+	req, report := withTrace(req, c.traceFn)
+	resp, err := c.roundTrip(req)
+	report()
+
+	return resp, err
+}
+
+func (c *Client) postRequest(ctx context.Context, routePath string, body io.Reader) (*Secret, error) {
+
+	ctx = withRequestID(ctx)
+
+	responseBody, header, err := c.postRaw(ctx, routePath, body)
 	if err != nil {
-		log.Println("POST: Unable to read response into byte array.")
 		return nil, err
 	}
+
+	responseBody = c.unwrapRecord(responseBody)
+	if isBurnRoute(routePath) {
+		responseBody = burnedState(responseBody)
+	}
+
 	var otsResponse *Secret
 
-	err = json.Unmarshal(responseBody, &otsResponse)
+	err = c.decode(responseBody, &otsResponse)
 	if err != nil {
-		log.Println("POST: Unable to unmarshal JSON response.")
-		return nil, err
+		c.debug(ctx, "unable to decode response", "method", "POST", "route", maskRoute(routePath), "error", err)
+		return nil, c.requestError(ctx, "POST", routePath, err)
+	}
+
+	if otsResponse != nil {
+		otsResponse.setExpiry(serverTime(header))
+	}
+
+	if c.responseValidator != nil {
+		if err := c.responseValidator(routePath, otsResponse); err != nil {
+			return nil, err
+		}
 	}
 
 	return otsResponse, nil
 
 }
 
+// postRaw sends a POST request to the route and returns the response body and headers, or an error if the API
+// returned a message.
+func (c *Client) postRaw(ctx context.Context, routePath string, body io.Reader) ([]byte, http.Header, error) {
+
+	ctx = withRequestID(ctx)
+
+	resp, err := c.do(ctx, "POST", routePath, body)
+	if err != nil {
+		c.debug(ctx, "unable to send request", "method", "POST", "route", maskRoute(routePath), "error", err)
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		c.debug(ctx, "unable to read response", "method", "POST", "route", maskRoute(routePath), "error", err)
+		return nil, nil, c.requestError(ctx, "POST", routePath, err)
+	}
+
+	if err := checkResponse(resp.StatusCode, responseBody); err != nil {
+		return nil, nil, c.requestError(ctx, "POST", routePath, err)
+	}
+
+	return responseBody, resp.Header, nil
+}
+
+// baseURL returns the API base requests are sent to, without any trailing slashes.
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return base
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// isBurnRoute reports whether the route is the burn endpoint, private/METADATA_KEY/burn.
+func isBurnRoute(routePath string) bool {
+	return strings.HasPrefix(routePath, "private/") && strings.HasSuffix(routePath, "/burn")
+}
+
+// burnResponse is the response to a burn request, which contains the burned secret's metadata under state.
+type burnResponse struct {
+	State          json.RawMessage `json:"state"`
+	SecretShortkey string          `json:"secret_shortkey,omitempty"`
+}
+
+// burnedState returns the metadata from a burn response. Servers which return the metadata directly, rather than
+// within state, have their response returned unchanged.
+func burnedState(body []byte) []byte {
+
+	var resp burnResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return body
+	}
+
+	if state := bytes.TrimSpace(resp.State); len(state) > 0 && state[0] == '{' {
+		return state
+	}
+
+	return body
+}
+
+// stripSecretFields removes the secret and passphrase from a form body, as a guard against them being sent to an
+// endpoint which does not expect them.
+func stripSecretFields(payload []byte) []byte {
+
+	v, err := url.ParseQuery(string(payload))
+	if err != nil {
+		return nil
+	}
+
+	v.Del("secret")
+	v.Del("passphrase")
+
+	return []byte(v.Encode())
+}
+
+// generatePassphrase returns a random alphanumeric string of length n, using crypto/rand as the source.
+func generatePassphrase(n int) (string, error) {
+
+	if n <= 0 {
+		return "", fmt.Errorf("passphrase length must be positive, got %d", n)
+	}
+
+	max := big.NewInt(int64(len(passphraseChars)))
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = passphraseChars[idx.Int64()]
+	}
+
+	return string(b), nil
+}
 
-func createURI(s string) string {
-	URI := fmt.Sprintf("%s/%s", base, s)
+func createURI(baseURL, s string) string {
+	URI := fmt.Sprintf("%s/%s", baseURL, s)
 	return URI
 }
@@ -0,0 +1,497 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Run with a local time zone other than UTC, so that any timestamp which is not converted to UTC is caught.
+	// This is set before any test starts, as changing time.Local while servers are running is a data race.
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+
+	os.Exit(m.Run())
+}
+
+// recordedRequest is a request received by a testServer.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Form   url.Values
+}
+
+// testServer is an OTS server for tests, which records every request it receives.
+type testServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+// newTestServer starts a testServer which passes each request to handler after recording it.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *testServer {
+	t.Helper()
+
+	ts := &testServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Errorf("parsing request body %q: %v", body, err)
+		}
+
+		ts.mu.Lock()
+		ts.requests = append(ts.requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Header: r.Header.Clone(),
+			Form:   form,
+		})
+		ts.mu.Unlock()
+
+		handler(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// respond returns a handler which always responds with status and body.
+func respond(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+// client returns a client for the server with test credentials.
+func (ts *testServer) client(opts ...Option) *Client {
+	return NewWithURL("user@example.com", "token", ts.URL, opts...)
+}
+
+// last returns the most recent request received by the server.
+func (ts *testServer) last(t *testing.T) recordedRequest {
+	t.Helper()
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if len(ts.requests) == 0 {
+		t.Fatal("the server received no requests")
+	}
+	return ts.requests[len(ts.requests)-1]
+}
+
+// count returns how many requests the server has received.
+func (ts *testServer) count() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return len(ts.requests)
+}
+
+func TestRequests(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		response string
+		call     func(c *Client) (*Secret, error)
+		method   string
+		path     string
+		form     url.Values
+		want     Secret
+	}{
+		{
+			name:     "create",
+			response: `{"custid":"user@example.com","metadata_key":"metakey","secret_key":"secretkey","ttl":3600,"state":"new"}`,
+			call: func(c *Client) (*Secret, error) {
+				return c.Create("hunter2", "pass", "bob@example.com", 3600)
+			},
+			method: "POST",
+			path:   "/share",
+			form:   url.Values{"secret": {"hunter2"}, "passphrase": {"pass"}, "recipient": {"bob@example.com"}, "ttl": {"3600"}},
+			want:   Secret{CustomerID: "user@example.com", MetadataKey: "metakey", SecretKey: "secretkey", TTL: 3600, State: "new", Recipient: []string{"bob@example.com"}},
+		},
+		{
+			name:     "generate",
+			response: `{"metadata_key":"metakey","secret_key":"secretkey","value":"generated"}`,
+			call: func(c *Client) (*Secret, error) {
+				return c.Generate("", "pass", 60)
+			},
+			method: "POST",
+			path:   "/generate",
+			form:   url.Values{"passphrase": {"pass"}, "ttl": {"60"}},
+			want:   Secret{MetadataKey: "metakey", SecretKey: "secretkey", Value: "generated"},
+		},
+		{
+			name:     "retrieve",
+			response: `{"secret_key":"secretkey","value":"hunter2"}`,
+			call: func(c *Client) (*Secret, error) {
+				return c.Retrieve("secretkey", "pass")
+			},
+			method: "POST",
+			path:   "/secret/secretkey",
+			form:   url.Values{"secret_key": {"secretkey"}, "passphrase": {"pass"}},
+			want:   Secret{SecretKey: "secretkey", Value: "hunter2"},
+		},
+		{
+			name:     "retrieve metadata",
+			response: `{"metadata_key":"metakey","state":"received","received":1700000100}`,
+			call: func(c *Client) (*Secret, error) {
+				return c.RetrieveMetadata("metakey")
+			},
+			method: "POST",
+			path:   "/private/metakey",
+			form:   url.Values{},
+			want:   Secret{MetadataKey: "metakey", State: "received", Received: 1700000100},
+		},
+		{
+			name:     "burn",
+			response: `{"state":{"metadata_key":"metakey","state":"burned"},"secret_shortkey":"secr"}`,
+			call: func(c *Client) (*Secret, error) {
+				return c.Burn("metakey")
+			},
+			method: "POST",
+			path:   "/private/metakey/burn",
+			form:   url.Values{},
+			want:   Secret{MetadataKey: "metakey", State: "burned"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(http.StatusOK, tt.response))
+
+			got, err := tt.call(ts.client())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req := ts.last(t)
+			if req.Method != tt.method || req.Path != tt.path {
+				t.Errorf("request = %s %s, want %s %s", req.Method, req.Path, tt.method, tt.path)
+			}
+			if req.Form.Encode() != tt.form.Encode() {
+				t.Errorf("form = %q, want %q", req.Form.Encode(), tt.form.Encode())
+			}
+
+			if !got.MetadataEquals(&tt.want) || got.SecretKey != tt.want.SecretKey || got.Value != tt.want.Value ||
+				got.CustomerID != tt.want.CustomerID {
+				t.Errorf("secret = %#v, want %#v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusRequest(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	if err := ts.client().Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := ts.last(t)
+	if req.Method != "GET" || req.Path != "/status" {
+		t.Errorf("request = %s %s, want GET /status", req.Method, req.Path)
+	}
+}
+
+func TestRecentMetadataRequest(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `[{"metadata_key":"one","state":"new"},{"metadata_key":"two","state":"received"}]`))
+
+	recent, err := ts.client().RetrieveRecentMetadata()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := ts.last(t)
+	if req.Method != "GET" || req.Path != "/private/recent" {
+		t.Errorf("request = %s %s, want GET /private/recent", req.Method, req.Path)
+	}
+
+	if len(*recent) != 2 || (*recent)[0].MetadataKey != "one" || (*recent)[1].State != "received" {
+		t.Errorf("recent = %v", *recent)
+	}
+}
+
+func TestAuthHeader(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	if err := ts.client().Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := &http.Request{Header: ts.last(t).Header}
+	user, token, ok := r.BasicAuth()
+	if !ok || user != "user@example.com" || token != "token" {
+		t.Errorf("basic auth = %q, %q, %v, want the client's credentials", user, token, ok)
+	}
+}
+
+func TestAnonymousSendsNoAuthHeader(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	if err := NewWithURL("", "", ts.URL, WithAnonymous()).Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth := ts.last(t).Header.Get("Authorization"); auth != "" {
+		t.Errorf("Authorization = %q, want none", auth)
+	}
+}
+
+func TestMissingCredentials(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	err := NewWithURL("", "", ts.URL).Status()
+	if !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("error = %v, want ErrMissingCredentials", err)
+	}
+
+	if n := ts.count(); n != 0 {
+		t.Errorf("the server received %d requests, want 0", n)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusBadRequest, `{"message":"You did not provide anything to share"}`))
+
+	_, err := ts.client().Create("", "", "", 0)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "You did not provide anything to share" {
+		t.Errorf("APIError = %d %q", apiErr.StatusCode, apiErr.Message)
+	}
+}
+
+func TestErrorStatusWithoutMessage(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusInternalServerError, `<html>oops</html>`))
+
+	_, err := ts.client().RetrieveMetadata("metakey")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusInternalServerError || string(apiErr.Raw) != "<html>oops</html>" {
+		t.Errorf("APIError = %d %q", apiErr.StatusCode, apiErr.Raw)
+	}
+}
+
+func TestMalformedJSON(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":`))
+
+	_, err := ts.client().Create("hunter2", "", "", 60)
+	if err == nil {
+		t.Fatal("expected an error for a malformed response")
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("error = %v, want a decoding error rather than an *APIError", err)
+	}
+}
+
+func TestMalformedRecentMetadata(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `[{"metadata_key":"one"`))
+
+	if _, err := ts.client().RetrieveRecentMetadata(); err == nil {
+		t.Fatal("expected an error for a malformed response")
+	}
+}
+
+func TestRecentPassphraseEnrichment(t *testing.T) {
+
+	ts := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/private/recent":
+			respond(http.StatusOK, `[{"metadata_key":"protected"},{"metadata_key":"open"},{"metadata_key":"known","passphrase_required":true}]`)(w, r)
+		case "/private/protected":
+			respond(http.StatusOK, `{"metadata_key":"protected","passphrase_required":true}`)(w, r)
+		case "/private/open":
+			respond(http.StatusOK, `{"metadata_key":"open"}`)(w, r)
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			respond(http.StatusNotFound, `{"message":"Unknown secret"}`)(w, r)
+		}
+	})
+
+	plain, err := ts.client().RetrieveRecentMetadata()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (*plain)[0].PassphraseRequired || ts.count() != 1 {
+		t.Errorf("without enrichment, PassphraseRequired = %v after %d requests, want false after 1", (*plain)[0].PassphraseRequired, ts.count())
+	}
+
+	recent, err := ts.client(WithRecentPassphraseEnrichment()).RetrieveRecentMetadata()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []bool{true, false, true}
+	for i, s := range *recent {
+		if s.PassphraseRequired != want[i] {
+			t.Errorf("%s PassphraseRequired = %v, want %v", s.MetadataKey, s.PassphraseRequired, want[i])
+		}
+	}
+
+	// The recent metadata and one request for each secret which did not already need a passphrase.
+	if n := ts.count(); n != 1+3 {
+		t.Errorf("the server received %d requests, want 4", n)
+	}
+}
+
+func TestBurnStripsSecretFields(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"state":{"metadata_key":"metakey","state":"burned"}}`))
+
+	body := strings.NewReader(url.Values{"secret": {"hunter2"}, "passphrase": {"pass"}, "reason": {"leaked"}}.Encode())
+	resp, err := ts.client().do(context.Background(), "POST", "private/metakey/burn", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := ts.last(t).Form.Encode(); got != "reason=leaked" {
+		t.Errorf("burn form = %q, want only reason=leaked", got)
+	}
+
+	if got := string(stripSecretFields([]byte("secret=a&passphrase=b"))); got != "" {
+		t.Errorf("stripSecretFields = %q, want empty", got)
+	}
+}
+
+func TestBurnResponse(t *testing.T) {
+
+	// A burn response as sent by onetimesecret.com, with the secret nested under state.
+	body := `{
+		"state": {
+			"custid": "user@example.com",
+			"metadata_key": "qjpjroeit8wra0ojeyhcw5pjsgwtuq7",
+			"secret_key": "",
+			"recipient": [],
+			"ttl": 3600,
+			"metadata_ttl": 3520,
+			"secret_ttl": 0,
+			"state": "burned",
+			"updated": 1700000080,
+			"created": 1700000000,
+			"passphrase_required": true
+		},
+		"secret_shortkey": "6ofc9ijg"
+	}`
+
+	ts := newTestServer(t, respond(http.StatusOK, body))
+
+	s, err := ts.client().Burn("qjpjroeit8wra0ojeyhcw5pjsgwtuq7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.MetadataKey != "qjpjroeit8wra0ojeyhcw5pjsgwtuq7" || s.State != "burned" || s.CustomerID != "user@example.com" {
+		t.Errorf("secret = %#v", *s)
+	}
+	if s.TTL != 3600 || s.MetadataTTL != 3520 || s.Updated != 1700000080 || !s.PassphraseRequired {
+		t.Errorf("secret = %#v", *s)
+	}
+	if len(s.Recipient) != 0 || !s.SecretExpiresAt.IsZero() || s.MetadataExpiresAt.IsZero() {
+		t.Errorf("secret = %#v", *s)
+	}
+}
+
+func TestBurnResponseWithoutState(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey","state":"burned"}`))
+
+	s, err := ts.client().Burn("metakey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.MetadataKey != "metakey" || s.State != "burned" {
+		t.Errorf("secret = %#v", *s)
+	}
+}
+
+func TestRecentMetadataShapes(t *testing.T) {
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "empty array", body: `[]`, want: 0},
+		{name: "null", body: `null`, want: 0},
+		{name: "metadata", body: `[{"metadata_key":"one","secret_ttl":60}]`, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, respond(http.StatusOK, tt.body))
+
+			recent, err := ts.client().RetrieveRecentMetadata()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if recent == nil || *recent == nil {
+				t.Fatal("recent metadata is nil, want an empty Secrets")
+			}
+			if len(*recent) != tt.want {
+				t.Fatalf("recent = %v, want %d secrets", *recent, tt.want)
+			}
+			for _, s := range *recent {
+				if s.SecretExpiresAt.IsZero() {
+					t.Errorf("%s has no SecretExpiresAt", s.MetadataKey)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyFormFieldsOmitted(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+	c := ts.client()
+
+	if _, err := c.Create("hunter2", "", "", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ts.last(t).Form.Encode(); got != "secret=hunter2" {
+		t.Errorf("create form = %q, want only the secret", got)
+	}
+
+	if _, err := c.Generate("", "", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	form := ts.last(t).Form
+	for _, key := range []string{"passphrase", "recipient", "ttl"} {
+		if _, ok := form[key]; ok {
+			t.Errorf("generate form %q has a %s key", form.Encode(), key)
+		}
+	}
+}
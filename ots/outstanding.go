@@ -0,0 +1,55 @@
+package ots
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// OutstandingSecret is a secret which has not yet been viewed by its recipient, as returned by OutstandingSecrets.
+type OutstandingSecret struct {
+	MetadataKey string
+	Recipient   []string
+	Created     time.Time
+	Age         time.Duration
+}
+
+// OutstandingSecrets returns the recent secrets which have not yet been viewed, along with how long each has been
+// outstanding, sorted oldest first. Secrets which have been viewed or burned are left out. Recipients are masked.
+// This is built on RetrieveRecentMetadata.
+func (c *Client) OutstandingSecrets(ctx context.Context) ([]OutstandingSecret, error) {
+
+	recent, err := c.RetrieveRecentMetadataContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	outstanding := []OutstandingSecret{}
+
+	if recent != nil {
+		for _, s := range *recent {
+			if s.Viewed() || s.State == "burned" {
+				continue
+			}
+
+			recipients := make([]string, len(s.Recipient))
+			for i, r := range s.Recipient {
+				recipients[i] = maskEmail(r)
+			}
+
+			outstanding = append(outstanding, OutstandingSecret{
+				MetadataKey: s.MetadataKey,
+				Recipient:   recipients,
+				Created:     s.CreatedTime(),
+				Age:         s.Age(now),
+			})
+		}
+	}
+
+	sort.SliceStable(outstanding, func(i, j int) bool {
+		return outstanding[i].Created.Before(outstanding[j].Created)
+	})
+
+	return outstanding, nil
+}
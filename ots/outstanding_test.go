@@ -0,0 +1,36 @@
+package ots
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestOutstandingSecrets(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `[
+		{"metadata_key":"newer","state":"new","created":1700000200,"recipient":["bob@example.com"]},
+		{"metadata_key":"viewed","state":"received","created":1700000000},
+		{"metadata_key":"received","state":"new","created":1700000000,"received":1700000050},
+		{"metadata_key":"burned","state":"burned","created":1700000000},
+		{"metadata_key":"older","state":"new","created":1700000100}
+	]`))
+
+	outstanding, err := ts.client().OutstandingSecrets(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var keys []string
+	for _, s := range outstanding {
+		keys = append(keys, s.MetadataKey)
+	}
+	if want := []string{"older", "newer"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("outstanding = %q, want %q", keys, want)
+	}
+
+	if len(outstanding) == 2 && !reflect.DeepEqual(outstanding[1].Recipient, []string{"b***@example.com"}) {
+		t.Errorf("recipient = %q, want it masked", outstanding[1].Recipient)
+	}
+}
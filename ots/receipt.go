@@ -0,0 +1,53 @@
+package ots
+
+import (
+	"context"
+	"time"
+)
+
+// ShareReceipt is a record of a created secret which is safe to store, as returned by CreateReceipt.
+// It never contains the secret's value or passphrase.
+type ShareReceipt struct {
+	MetadataKey string
+
+	// The recipients given on creation with the local part masked, such as j***@example.com, separated by ", ".
+	// Empty if there were none.
+	Recipient string
+
+	Created time.Time
+
+	// When the secret expires, calculated from the server's remaining TTL, or from the creation time and the TTL
+	// when the server did not report one.
+	ExpiresAt time.Time
+
+	// The link the recipient visits to view the secret.
+	ShareURL string
+
+	// Whether OTS was asked to email the secret to the recipient.
+	EmailRequested bool
+}
+
+// CreateReceipt creates a secret with CreateWithOptions and returns a ShareReceipt for it, so that the same record
+// can be kept by every service which shares secrets.
+func (c *Client) CreateReceipt(ctx context.Context, opts CreateOptions) (*ShareReceipt, error) {
+
+	s, err := c.CreateWithOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &ShareReceipt{
+		MetadataKey:    s.MetadataKey,
+		Created:        s.CreatedTime(),
+		ExpiresAt:      s.SecretExpiresAt,
+		ShareURL:       c.SecretURL(s),
+		Recipient:      maskEmails(opts.recipients()),
+		EmailRequested: len(opts.recipients()) > 0,
+	}
+
+	if receipt.ExpiresAt.IsZero() && s.Created != 0 && s.TTL > 0 {
+		receipt.ExpiresAt = receipt.Created.Add(time.Duration(s.TTL) * time.Second)
+	}
+
+	return receipt, nil
+}
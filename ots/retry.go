@@ -0,0 +1,166 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultRetryBackoff is the delay before the first retry when WithMaxRetries is used without WithRetryBackoff.
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// maxRetryBackoff caps the delay between retries, however many retries have been made.
+const maxRetryBackoff = 30 * time.Second
+
+// WithMaxRetries sets how many times a request is retried after a transient failure: a 429 or 5xx response, or an
+// error sending the request. Retries are disabled by default. As retrying Create or Generate after the server has
+// received the request could create a duplicate secret, and retrying Retrieve could consume the secret without
+// returning its value, those are only retried when a connection could not be made.
+//
+// The delay between retries grows exponentially from the WithRetryBackoff base, with jitter. Retrying stops when
+// ctx is cancelled, or when the next delay would pass its deadline.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, which doubles for each further retry up to 30 seconds.
+// The default is 500 milliseconds. It has no effect unless WithMaxRetries is used.
+func WithRetryBackoff(base time.Duration) Option {
+	return func(c *Client) {
+		c.retryBackoff = base
+	}
+}
+
+// WithRetryPredicate sets a function which decides whether a failed attempt is retried, replacing the default
+// classification described in WithMaxRetries. It is called with the response, or the error if no response was
+// received, and is evaluated after every attempt, including those for Create, Generate and Retrieve, so it is
+// responsible for avoiding duplicate or lost secrets. It may be called from multiple goroutines at once, so it must
+// be safe for concurrent use. WithMaxRetries still limits the number of retries and cancelling the context still stops them.
+func WithRetryPredicate(fn func(resp *http.Response, err error) bool) Option {
+	return func(c *Client) {
+		c.retryPredicate = fn
+	}
+}
+
+// retryable reports whether the attempt should be retried, using the predicate from WithRetryPredicate if one was set.
+func (c *Client) retryable(routePath string, resp *http.Response, err error) bool {
+	if c.retryPredicate != nil {
+		return c.retryPredicate(resp, err)
+	}
+	return shouldRetry(routePath, resp, err)
+}
+
+// shouldRetry reports whether a request to the route which resulted in resp or err should be retried.
+func shouldRetry(routePath string, resp *http.Response, err error) bool {
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if isUnsafeToResend(routePath) {
+			return isConnectionError(err)
+		}
+		return true
+	}
+
+	if isUnsafeToResend(routePath) {
+		return false
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// isCreateRoute reports whether the route creates a secret, so sending it twice could create a duplicate.
+func isCreateRoute(routePath string) bool {
+	return routePath == "share" || routePath == "generate"
+}
+
+// isRetrieveRoute reports whether the route retrieves a secret, which consumes it, so sending it twice could lose the
+// value if the server answered the first request.
+func isRetrieveRoute(routePath string) bool {
+	return strings.HasPrefix(routePath, "secret/")
+}
+
+// isUnsafeToResend reports whether sending the route again after the server may have received it could create a
+// duplicate secret or consume a secret whose value was never returned.
+func isUnsafeToResend(routePath string) bool {
+	return isCreateRoute(routePath) || isRetrieveRoute(routePath)
+}
+
+// isConnectionError reports whether err means that a connection to the server could not be made, so the request
+// was never received.
+func isConnectionError(err error) bool {
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryDelay returns how long to wait before the given retry, starting at 1 for the first. It doubles the backoff
+// for each retry, and a random jitter of up to half the delay is removed so that clients do not retry in step.
+func (c *Client) retryDelay(retry int) time.Duration {
+
+	d := c.retryBackoff
+	if d <= 0 {
+		d = defaultRetryBackoff
+	}
+
+	for i := 1; i < retry && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+
+	return d - time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryError describes why a response is being retried, for WithOnRetry.
+func retryError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	return &APIError{StatusCode: resp.StatusCode}
+}
+
+// discard reads and closes the body of a response which is not going to be returned, so that the connection
+// can be reused.
+func discard(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// pastDeadline reports whether waiting for d would pass the deadline of ctx.
+func pastDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Now().Add(d).After(deadline)
+}
+
+// sleep waits for d, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
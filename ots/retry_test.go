@@ -0,0 +1,117 @@
+package ots
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFallbackOnConnectionError(t *testing.T) {
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	fallback := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+
+	c := NewWithURL("user@example.com", "token", down.URL, WithFallbackBaseURLs(fallback.URL))
+
+	if _, err := c.Create("hunter2", "", "", 60); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := fallback.count(); n != 1 {
+		t.Errorf("the fallback received %d requests, want 1", n)
+	}
+}
+
+func TestNoUnsafeFallbackAfterTimeout(t *testing.T) {
+
+	release := make(chan struct{})
+	slow := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer close(release)
+
+	fallback := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+
+	c := NewWithURL("user@example.com", "token", slow.URL,
+		WithFallbackBaseURLs(fallback.URL), WithTimeout(50*time.Millisecond))
+
+	if _, err := c.Create("hunter2", "", "", 60); err == nil {
+		t.Fatal("expected an error when the server times out")
+	}
+	if n := fallback.count(); n != 0 {
+		t.Errorf("the fallback received %d create requests, want 0", n)
+	}
+
+	if _, err := c.Retrieve("secretkey", ""); err == nil {
+		t.Fatal("expected an error when the server times out")
+	}
+	if n := fallback.count(); n != 0 {
+		t.Errorf("the fallback received %d retrieve requests, want 0", n)
+	}
+
+	if _, err := c.RetrieveMetadata("metakey"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := fallback.count(); n != 1 {
+		t.Errorf("the fallback received %d metadata requests, want 1", n)
+	}
+}
+
+func TestRetrieveNotRetriedAfterResponse(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusBadGateway, `{"message":"Bad gateway"}`))
+
+	c := ts.client(WithMaxRetries(2), WithRetryBackoff(time.Millisecond))
+
+	_, err := c.Retrieve("secretkey", "")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("error = %v, want the *APIError for the 502", err)
+	}
+	if n := ts.count(); n != 1 {
+		t.Errorf("the server received %d retrieve requests, want 1", n)
+	}
+
+	if _, err := c.RetrieveMetadata("metakey"); err == nil {
+		t.Fatal("expected an error when the server fails")
+	}
+	if n := ts.count(); n != 4 {
+		t.Errorf("the server received %d requests after retrying metadata, want 4", n)
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+
+	dialErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	tests := []struct {
+		route string
+		resp  *http.Response
+		err   error
+		want  bool
+	}{
+		{route: "share", err: dialErr, want: true},
+		{route: "share", err: readErr, want: false},
+		{route: "share", resp: unavailable, want: false},
+		{route: "secret/secretkey", err: dialErr, want: true},
+		{route: "secret/secretkey", err: readErr, want: false},
+		{route: "secret/secretkey", resp: unavailable, want: false},
+		{route: "private/metakey", err: readErr, want: true},
+		{route: "private/metakey", resp: unavailable, want: true},
+		{route: "private/metakey", resp: &http.Response{StatusCode: http.StatusNotFound}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := shouldRetry(tt.route, tt.resp, tt.err); got != tt.want {
+			t.Errorf("shouldRetry(%q, %v, %v) = %v, want %v", tt.route, tt.resp, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ErrorRoutes controls whether errors from a request include the route it was sent to, such as "share" or
+// "secret/SECRET_KEY". Routes can contain secret and metadata keys, which should not end up in logs.
+type ErrorRoutes int
+
+const (
+	// ErrorRoutesMasked includes the route with any keys masked, such as "secret/abcd****". This is the default.
+	ErrorRoutesMasked ErrorRoutes = iota
+
+	// ErrorRoutesFull includes the route unchanged, including any keys.
+	ErrorRoutesFull
+
+	// ErrorRoutesNone does not include the route.
+	ErrorRoutesNone
+)
+
+// WithErrorRoutes sets whether errors from requests include the route, which helps debugging but can leak keys.
+func WithErrorRoutes(r ErrorRoutes) Option {
+	return func(c *Client) {
+		c.errorRoutes = r
+	}
+}
+
+// RequestError is returned when a request to the OTS API fails. The underlying error is available with errors.Is
+// and errors.As. Transport errors are unwrapped from *url.Error, as it contains the full URL and any keys within it.
+type RequestError struct {
+	Method string
+
+	// The ID of the request, as set by ContextWithRequestID or generated for the request.
+	RequestID string
+
+	// The route the request was sent to, as configured by WithErrorRoutes. Empty when routes are not included.
+	Route string
+
+	Err error
+}
+
+func (e *RequestError) Error() string {
+
+	msg := e.Method
+	if e.Route != "" {
+		msg += " " + e.Route
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// requestError wraps err in a RequestError for the route, formatted according to the client's ErrorRoutes.
+func (c *Client) requestError(ctx context.Context, method, routePath string, err error) error {
+
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
+	}
+
+	re := &RequestError{Method: method, RequestID: RequestIDFromContext(ctx), Err: err}
+
+	switch c.errorRoutes {
+	case ErrorRoutesFull:
+		re.Route = routePath
+	case ErrorRoutesMasked:
+		re.Route = maskRoute(routePath)
+	}
+
+	return re
+}
+
+// maskRoute masks any keys in a route, keeping the first four characters so that a key can still be recognised.
+func maskRoute(routePath string) string {
+
+	parts := strings.Split(routePath, "/")
+	for i := 1; i < len(parts); i++ {
+		if parts[i-1] != "secret" && parts[i-1] != "private" || parts[i] == "recent" {
+			continue
+		}
+		parts[i] = maskKey(parts[i])
+	}
+
+	return strings.Join(parts, "/")
+}
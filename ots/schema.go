@@ -0,0 +1,121 @@
+package ots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// generateFields are the fields which a generate response is expected to contain.
+var generateFields = []string{"custid", "metadata_key", "secret_key", "value", "ttl", "created", "updated", "state"}
+
+// SchemaError is returned by CheckSchema when the server's response does not match the Secret model.
+type SchemaError struct {
+
+	// Fields in the response which Secret does not have.
+	Unknown []string
+
+	// Fields which Secret expects but the response did not contain.
+	Missing []string
+}
+
+func (e *SchemaError) Error() string {
+
+	var problems []string
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown fields: "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing fields: "+strings.Join(e.Missing, ", "))
+	}
+
+	return "ots: response does not match the Secret model, " + strings.Join(problems, "; ")
+}
+
+// CheckSchema checks that the server's responses match the Secret model, so that an upgrade which changed the API
+// can be detected before real operations break. A secret is generated with a short TTL and then immediately burned,
+// and the generate response is compared to the fields of Secret. A *SchemaError is returned listing any unknown or
+// missing fields. If the response has no metadata key, the secret cannot be burned and an error wrapping the
+// *SchemaError is returned. This requires credentials with permission to generate and burn secrets, and is safe to
+// run at startup.
+func (c *Client) CheckSchema(ctx context.Context) error {
+
+	v := url.Values{}
+	v.Set("ttl", "60")
+
+	body, _, err := c.postRaw(ctx, "generate", strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.unwrapRecord(body), &fields); err != nil {
+		return fmt.Errorf("ots: decoding generate response: %w", err)
+	}
+
+	key := metadataKeyFrom(fields)
+	if key != "" {
+		if _, err := c.BurnContext(ctx, key); err != nil {
+			return fmt.Errorf("ots: burning schema check secret: %w", err)
+		}
+	}
+
+	known := secretFields()
+	schemaErr := &SchemaError{}
+
+	for name := range fields {
+		if !known[name] {
+			schemaErr.Unknown = append(schemaErr.Unknown, name)
+		}
+	}
+
+	for _, name := range generateFields {
+		if _, ok := fields[name]; !ok {
+			schemaErr.Missing = append(schemaErr.Missing, name)
+		}
+	}
+
+	sort.Strings(schemaErr.Unknown)
+
+	if key == "" {
+		return fmt.Errorf("ots: generate response has no metadata key, the schema check secret was not burned: %w", schemaErr)
+	}
+
+	if len(schemaErr.Unknown) == 0 && len(schemaErr.Missing) == 0 {
+		return nil
+	}
+
+	return schemaErr
+}
+
+// secretFields returns the JSON field names of Secret.
+func secretFields() map[string]bool {
+
+	fields := make(map[string]bool)
+
+	t := reflect.TypeOf(Secret{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+
+	return fields
+}
+
+// metadataKeyFrom returns the metadata key from raw response fields, accepting the camelCase name as Secret does,
+// or an empty string if there is none.
+func metadataKeyFrom(fields map[string]json.RawMessage) string {
+	for _, name := range []string{"metadata_key", "metadataKey"} {
+		var key string
+		if json.Unmarshal(fields[name], &key) == nil && key != "" {
+			return key
+		}
+	}
+	return ""
+}
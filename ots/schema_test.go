@@ -0,0 +1,74 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const schemaGenerateRecord = `{"custid":"user@example.com","metadata_key":"metakey","secret_key":"secretkey",` +
+	`"value":"generated","ttl":60,"created":1700000000,"updated":1700000000,"state":"new"}`
+
+func TestCheckSchema(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		version  APIVersion
+		generate string
+		burn     string
+	}{
+		{
+			name:     "v1",
+			generate: schemaGenerateRecord,
+			burn:     `{"metadata_key":"metakey","state":"burned"}`,
+		},
+		{
+			name:     "v2",
+			version:  APIV2,
+			generate: `{"success":true,"record":` + schemaGenerateRecord + `}`,
+			burn:     `{"success":true,"record":{"metadata_key":"metakey","state":"burned"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/burn") {
+					respond(http.StatusOK, tt.burn)(w, r)
+					return
+				}
+				respond(http.StatusOK, tt.generate)(w, r)
+			})
+
+			var opts []Option
+			if tt.version != "" {
+				opts = append(opts, WithAPIVersion(tt.version))
+			}
+
+			if err := ts.client(opts...).CheckSchema(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := ts.last(t).Path; got != "/private/metakey/burn" {
+				t.Errorf("last request = %s, want the schema check secret to be burned", got)
+			}
+		})
+	}
+}
+
+func TestCheckSchemaWithoutMetadataKey(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"custid":"user@example.com","secret_key":"secretkey","value":"generated"}`))
+
+	err := ts.client().CheckSchema(context.Background())
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || !strings.Contains(err.Error(), "not burned") {
+		t.Errorf("error = %v, want an error saying the secret was not burned, wrapping a *SchemaError", err)
+	}
+	if n := ts.count(); n != 1 {
+		t.Errorf("the server received %d requests, want only the generate request", n)
+	}
+}
@@ -0,0 +1,226 @@
+package ots
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// EntropyBits returns an estimate of the Shannon entropy, in bits, of the secret's Value. This is calculated from
+// the frequency of each character within the value itself, so it is a best-effort estimate and not a measure
+// of how the value was generated. An empty Value returns 0.
+func (s *Secret) EntropyBits() float64 {
+
+	if s.Value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s.Value {
+		counts[r]++
+		total++
+	}
+
+	var perChar float64
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+
+	return perChar * float64(total)
+}
+
+// expiryTolerance is how far apart two expiry times can be while MetadataEquals treats them as the same. Expiry times
+// are calculated from a remaining TTL and the server's clock, both in whole seconds, so they vary slightly between polls.
+const expiryTolerance = 2 * time.Second
+
+// MetadataEquals reports whether two secrets have the same state, timestamps, TTL and expiry times. The Value is
+// ignored, which makes this useful for deciding whether metadata has meaningfully changed between polls. The
+// remaining MetadataTTL and SecretTTL are not compared, as they count down between polls, but the expiry times
+// calculated from them are, within a small tolerance.
+func (s *Secret) MetadataEquals(other *Secret) bool {
+
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	return s.State == other.State &&
+		s.Created == other.Created &&
+		s.Updated == other.Updated &&
+		s.Received == other.Received &&
+		s.TTL == other.TTL &&
+		expiryEquals(s.SecretExpiresAt, other.SecretExpiresAt) &&
+		expiryEquals(s.MetadataExpiresAt, other.MetadataExpiresAt)
+}
+
+// expiryEquals reports whether two expiry times are both unset, or are within expiryTolerance of each other.
+func expiryEquals(a, b time.Time) bool {
+
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() == b.IsZero()
+	}
+
+	d := a.Sub(b)
+	return -expiryTolerance <= d && d <= expiryTolerance
+}
+
+// Viewed reports whether the recipient has viewed the secret, from its state or its Received timestamp. This is
+// intended for polling metadata to see when a secret has been opened.
+func (s *Secret) Viewed() bool {
+	return s.State == "received" || s.State == "viewed" || s.Received != 0
+}
+
+// Plaintext returns the value of a retrieved secret, which is empty for a secret that has only been created or
+// whose metadata has been retrieved.
+func (s *Secret) Plaintext() string {
+	if s == nil {
+		return ""
+	}
+	return s.Value
+}
+
+// WasGenerated reports whether the secret's value was generated by the server, as with Generate. A generated secret
+// is returned with both its Value and MetadataKey populated, whereas Create does not return the value and Retrieve
+// does not return the metadata key.
+func (s *Secret) WasGenerated() bool {
+	return s.Value != "" && s.MetadataKey != ""
+}
+
+// CreatedTime returns the time the secret was created, in UTC.
+func (s *Secret) CreatedTime() time.Time {
+	return time.Unix(s.Created, 0).UTC()
+}
+
+// UpdatedTime returns the time the secret was last updated, in UTC.
+func (s *Secret) UpdatedTime() time.Time {
+	return time.Unix(s.Updated, 0).UTC()
+}
+
+// Age returns how long it has been since the secret was created, relative to now.
+func (s *Secret) Age(now time.Time) time.Duration {
+	return now.Sub(s.CreatedTime())
+}
+
+// UnmarshalJSON decodes a Secret, accepting the recipient as either a single string or an array of strings,
+// as servers differ in which they return. The camelCase keys secretKey and metadataKey, used by some OTS-compatible
+// servers, are also accepted, as are secret_path for the SharePath and secret_value for the Value.
+func (s *Secret) UnmarshalJSON(b []byte) error {
+
+	type secret Secret
+	aux := struct {
+		*secret
+		Recipient json.RawMessage `json:"recipient,omitempty"`
+
+		SecretKeyCamel   string `json:"secretKey,omitempty"`
+		MetadataKeyCamel string `json:"metadataKey,omitempty"`
+		SecretPath       string `json:"secret_path,omitempty"`
+		SecretValue      string `json:"secret_value,omitempty"`
+	}{secret: (*secret)(s)}
+
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if s.SecretKey == "" {
+		s.SecretKey = aux.SecretKeyCamel
+	}
+	if s.MetadataKey == "" {
+		s.MetadataKey = aux.MetadataKeyCamel
+	}
+	if s.SharePath == "" {
+		s.SharePath = aux.SecretPath
+	}
+	if s.Value == "" {
+		s.Value = aux.SecretValue
+	}
+
+	recipients, err := parseRecipients(aux.Recipient)
+	if err != nil {
+		return err
+	}
+	s.Recipient = recipients
+
+	return nil
+}
+
+// parseRecipients decodes a recipient field which may be null, a string or an array of strings.
+func parseRecipients(raw json.RawMessage) ([]string, error) {
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		if one == "" {
+			return nil, nil
+		}
+		return []string{one}, nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, fmt.Errorf("recipient must be a string or array of strings: %w", err)
+	}
+
+	return many, nil
+}
+
+// setExpiry calculates the absolute expiry times from the remaining TTLs, relative to now as reported by the server.
+func (s *Secret) setExpiry(now time.Time) {
+
+	if s.SecretTTL > 0 {
+		s.SecretExpiresAt = now.Add(time.Duration(s.SecretTTL) * time.Second)
+	}
+
+	if s.MetadataTTL > 0 {
+		s.MetadataExpiresAt = now.Add(time.Duration(s.MetadataTTL) * time.Second)
+	}
+}
+
+// serverTime returns the time from the Date header of a response, so that expiry times are not affected by clock
+// skew between the client and the server. The local time is used if the header is missing or invalid. The time
+// is always in UTC, so the expiry times calculated from it are too.
+func serverTime(header http.Header) time.Time {
+
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		return date.UTC()
+	}
+
+	return time.Now().UTC()
+}
+
+// String describes the secret with its Value redacted and its SecretKey masked, so that printing a secret, such as
+// in a log or an error, never reveals it.
+func (s Secret) String() string {
+
+	value := ""
+	if s.Value != "" {
+		value = "[redacted]"
+	}
+
+	return fmt.Sprintf("{MetadataKey:%s SecretKey:%s Value:%s State:%s Recipient:%v TTL:%d}",
+		s.MetadataKey, maskKey(s.SecretKey), value, s.State, s.Recipient, s.TTL)
+}
+
+// GoString is the same as String, for the %#v verb.
+func (s Secret) GoString() string {
+	return "ots.Secret" + s.String()
+}
+
+// maskKey keeps the first 4 characters of a key, which is enough to tell keys apart, and masks the rest.
+func maskKey(key string) string {
+
+	if key == "" {
+		return ""
+	}
+
+	if len(key) > 4 {
+		return key[:4] + "****"
+	}
+
+	return "****"
+}
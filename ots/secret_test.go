@@ -0,0 +1,285 @@
+package ots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetadataEquals(t *testing.T) {
+
+	expires := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := &Secret{
+		State:             "new",
+		Created:           1700000000,
+		Updated:           1700000000,
+		TTL:               3600,
+		MetadataTTL:       7200,
+		SecretTTL:         3600,
+		SecretExpiresAt:   expires,
+		MetadataExpiresAt: expires.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *Secret)
+		want   bool
+	}{
+		{name: "unchanged", change: func(s *Secret) {}, want: true},
+		{
+			name: "remaining TTLs counted down",
+			change: func(s *Secret) {
+				s.MetadataTTL -= 30
+				s.SecretTTL -= 30
+				s.SecretExpiresAt = s.SecretExpiresAt.Add(time.Second)
+			},
+			want: true,
+		},
+		{name: "value differs", change: func(s *Secret) { s.Value = "hunter2" }, want: true},
+		{name: "state changed", change: func(s *Secret) { s.State = "received" }, want: false},
+		{name: "received", change: func(s *Secret) { s.Received = 1700000100 }, want: false},
+		{name: "updated", change: func(s *Secret) { s.Updated++ }, want: false},
+		{name: "TTL changed", change: func(s *Secret) { s.TTL = 60 }, want: false},
+		{name: "expiry moved", change: func(s *Secret) { s.SecretExpiresAt = s.SecretExpiresAt.Add(time.Minute) }, want: false},
+		{name: "expiry unset", change: func(s *Secret) { s.MetadataExpiresAt = time.Time{} }, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := *s
+			tt.change(&other)
+
+			if got := s.MetadataEquals(&other); got != tt.want {
+				t.Errorf("MetadataEquals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !(*Secret)(nil).MetadataEquals(nil) || s.MetadataEquals(nil) {
+		t.Error("MetadataEquals should only treat nil as equal to nil")
+	}
+}
+
+func TestValueEncrypted(t *testing.T) {
+
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{body: `{"metadata_key":"metakey","value_encrypted":true}`, want: true},
+		{body: `{"metadata_key":"metakey","value_encrypted":false}`, want: false},
+		{body: `{"metadata_key":"metakey"}`, want: false},
+	}
+
+	for _, tt := range tests {
+		ts := newTestServer(t, respond(http.StatusOK, tt.body))
+
+		s, err := ts.client().RetrieveMetadata("metakey")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if s.ValueEncrypted != tt.want {
+			t.Errorf("ValueEncrypted for %s = %v, want %v", tt.body, s.ValueEncrypted, tt.want)
+		}
+	}
+}
+
+func TestDecodeRecipient(t *testing.T) {
+
+	tests := []struct {
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{body: `{"recipient":"bob@example.com"}`, want: []string{"bob@example.com"}},
+		{body: `{"recipient":["bob@example.com","alice@example.com"]}`, want: []string{"bob@example.com", "alice@example.com"}},
+		{body: `{"recipient":""}`, want: nil},
+		{body: `{"recipient":null}`, want: nil},
+		{body: `{}`, want: nil},
+		{body: `{"recipient":42}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var s Secret
+		err := json.Unmarshal([]byte(tt.body), &s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decoding %s: error = %v, want error %v", tt.body, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(s.Recipient, tt.want) {
+			t.Errorf("decoding %s: recipient = %q, want %q", tt.body, s.Recipient, tt.want)
+		}
+	}
+}
+
+func TestCreateFillsInRecipient(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+
+	s, err := ts.client().Create("hunter2", "", "bob@example.com", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"bob@example.com"}; !reflect.DeepEqual(s.Recipient, want) {
+		t.Errorf("recipient = %q, want %q", s.Recipient, want)
+	}
+}
+
+func TestDecodeKeyVariants(t *testing.T) {
+
+	tests := []struct {
+		body               string
+		secretKey, metaKey string
+	}{
+		{body: `{"secret_key":"snake","metadata_key":"metasnake"}`, secretKey: "snake", metaKey: "metasnake"},
+		{body: `{"secretKey":"camel","metadataKey":"metacamel"}`, secretKey: "camel", metaKey: "metacamel"},
+		{body: `{"secret_key":"snake","secretKey":"camel"}`, secretKey: "snake"},
+	}
+
+	for _, tt := range tests {
+		var s Secret
+		if err := json.Unmarshal([]byte(tt.body), &s); err != nil {
+			t.Fatalf("decoding %s: %v", tt.body, err)
+		}
+		if s.SecretKey != tt.secretKey || s.MetadataKey != tt.metaKey {
+			t.Errorf("decoding %s: keys = %q, %q, want %q, %q", tt.body, s.SecretKey, s.MetadataKey, tt.secretKey, tt.metaKey)
+		}
+	}
+}
+
+func TestRecipientRoundTrip(t *testing.T) {
+
+	in := Secret{MetadataKey: "metakey", Recipient: []string{"bob@example.com", "alice@example.com"}}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(fields["recipient"]); got != `["bob@example.com","alice@example.com"]` {
+		t.Errorf("recipient in %s = %s, want the recipients under the recipient key", b, got)
+	}
+
+	var out Secret
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Recipient, in.Recipient) {
+		t.Errorf("round trip recipient = %q, want %q", out.Recipient, in.Recipient)
+	}
+}
+
+func TestPassphraseHint(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey","passphrase_required":true,"passphrase_hint":"your employee ID"}`))
+
+	s, err := ts.client().RetrieveMetadata("metakey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.PassphraseHint != "your employee ID" || !s.PassphraseRequired {
+		t.Errorf("hint, required = %q, %v, want %q, true", s.PassphraseHint, s.PassphraseRequired, "your employee ID")
+	}
+}
+
+func TestRetrievePlaintext(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"secret_key":"secretkey","value":"hunter2"}`))
+
+	s, err := ts.client().Retrieve("secretkey", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Plaintext() != "hunter2" {
+		t.Errorf("Plaintext = %q, want hunter2", s.Plaintext())
+	}
+
+	if (*Secret)(nil).Plaintext() != "" {
+		t.Error("Plaintext of a nil secret should be empty")
+	}
+}
+
+func TestRetrieveEmptyValue(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"secret_key":"secretkey"}`))
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	_, err := ts.client().RetrieveContext(ctx, "secretkey", "")
+	if !errors.Is(err, ErrEmptySecret) || !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("error = %v, want ErrEmptySecret matching ErrSecretNotFound", err)
+	}
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.RequestID != "req-1" {
+		t.Errorf("error = %v, want a *RequestError with the request ID", err)
+	}
+}
+
+func TestTimestampsAreUTC(t *testing.T) {
+
+	if time.Local == time.UTC {
+		t.Fatal("the tests must run with a local time zone other than UTC, see TestMain")
+	}
+
+	ts := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", "Tue, 14 Nov 2023 22:13:20 GMT")
+		respond(http.StatusOK, `{"metadata_key":"metakey","created":1700000000,"updated":1700000100,"secret_ttl":60,"metadata_ttl":120}`)(w, r)
+	})
+
+	s, err := ts.client().RetrieveMetadata("metakey")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, got := range map[string]time.Time{
+		"CreatedTime":       s.CreatedTime(),
+		"UpdatedTime":       s.UpdatedTime(),
+		"SecretExpiresAt":   s.SecretExpiresAt,
+		"MetadataExpiresAt": s.MetadataExpiresAt,
+	} {
+		if got.Location() != time.UTC {
+			t.Errorf("%s is in %s, want UTC", name, got.Location())
+		}
+	}
+
+	if want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC); !s.CreatedTime().Equal(want) {
+		t.Errorf("CreatedTime = %s, want %s", s.CreatedTime(), want)
+	}
+	if want := time.Date(2023, 11, 14, 22, 14, 20, 0, time.UTC); !s.SecretExpiresAt.Equal(want) {
+		t.Errorf("SecretExpiresAt = %s, want %s", s.SecretExpiresAt, want)
+	}
+	if got := serverTime(http.Header{}); got.Location() != time.UTC {
+		t.Errorf("serverTime without a Date header is in %s, want UTC", got.Location())
+	}
+}
+
+func TestSecretFormattingRedacts(t *testing.T) {
+
+	s := Secret{MetadataKey: "metakey", SecretKey: "abcdefghijkl", Value: "hunter2", State: "new"}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		for _, v := range []interface{}{s, &s, []Secret{s}} {
+			got := fmt.Sprintf(format, v)
+			if strings.Contains(got, "hunter2") || strings.Contains(got, "abcdefghijkl") {
+				t.Errorf("Sprintf(%q, %T) = %q, which reveals the value or secret key", format, v, got)
+			}
+		}
+	}
+
+	if got := fmt.Sprintf("%v", s); !strings.Contains(got, "metakey") || !strings.Contains(got, "[redacted]") {
+		t.Errorf("Sprintf(%%v) = %q, want the metadata key and a redacted value", got)
+	}
+}
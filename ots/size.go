@@ -0,0 +1,83 @@
+package ots
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+)
+
+// WithMaxSecretSize sets the maximum size, in bytes, of a secret passed to Create. A larger secret returns
+// ErrSecretTooLarge before any request is sent, unless WithTruncateOversized is also used. This should match the
+// limit of your OTS account or server, a size of 0 means there is no limit, which is the default.
+func WithMaxSecretSize(n int) Option {
+	return func(c *Client) {
+		c.maxSecretSize = n
+	}
+}
+
+// WithTruncateOversized makes Create truncate a secret which is larger than the size set by WithMaxSecretSize,
+// rather than returning ErrSecretTooLarge. The returned Secret has Truncated set so that the caller can warn about
+// it, and the truncation is also logged to the logger set by WithLogger. This is only suitable for values where
+// losing the end is acceptable, such as logs, as truncating structured content like JSON will corrupt it.
+func WithTruncateOversized() Option {
+	return func(c *Client) {
+		c.truncateOversized = true
+	}
+}
+
+// WillFit reports whether value is within the size set by WithMaxSecretSize, and how many bytes remain below the
+// maximum, which is negative when value is over it. This allows a UI to show how much space is left before a
+// secret is submitted. The size is measured as the bytes Create sends, as the value is not encoded by the client.
+// If no maximum is set, WillFit returns true and 0. An oversized value is reported as not fitting even if the client
+// truncates oversized secrets.
+func (c *Client) WillFit(value string) (bool, int) {
+
+	if c.maxSecretSize <= 0 {
+		return true, 0
+	}
+
+	remaining := c.maxSecretSize - len(value)
+
+	return remaining >= 0, remaining
+}
+
+// checkSize returns the secret to send, truncated if the client allows it, or an error if it is too large. The
+// bool reports whether the secret was truncated.
+func (c *Client) checkSize(ctx context.Context, secret string) (string, bool, error) {
+
+	if c.maxSecretSize <= 0 || len(secret) <= c.maxSecretSize {
+		return secret, false, nil
+	}
+
+	if !c.truncateOversized {
+		return "", false, fmt.Errorf("%w: %d bytes exceeds the maximum of %d", ErrSecretTooLarge, len(secret), c.maxSecretSize)
+	}
+
+	truncated := secret[:truncationPoint(secret, c.maxSecretSize)]
+
+	c.debug(ctx, "secret truncated", "from_bytes", len(secret), "to_bytes", len(truncated))
+
+	return truncated, true, nil
+}
+
+// truncationPoint returns where to cut s so that it is at most max bytes, which is max unless that would split a
+// multi-byte character, in which case the cut is moved back to the start of the character. Invalid UTF-8 elsewhere
+// in s is left as it is.
+func truncationPoint(s string, max int) int {
+
+	if utf8.RuneStart(s[max]) {
+		return max
+	}
+
+	for i := max - 1; i >= 0 && i > max-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(s[i]) {
+			continue
+		}
+		if r, size := utf8.DecodeRuneInString(s[i:]); r != utf8.RuneError && i+size > max {
+			return i
+		}
+		break
+	}
+
+	return max
+}
@@ -0,0 +1,90 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestTruncateOversized(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		secret string
+		max    int
+		want   string
+	}{
+		{name: "ascii", secret: "abcdefghijklmnop", max: 10, want: "abcdefghij"},
+		{name: "fits", secret: "abc", max: 10, want: "abc"},
+		{name: "split rune", secret: "abcdefghi€", max: 10, want: "abcdefghi"},
+		{name: "whole rune", secret: "abcdefg€xyz", max: 10, want: "abcdefg€"},
+		{name: "leading invalid byte", secret: "\xff" + "abcdefghijklmnop", max: 10, want: "\xff" + "abcdefghi"},
+		{name: "invalid byte at cut", secret: "abcdefghi\x80xyz", max: 10, want: "abcdefghi\x80"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("user@example.com", "token", WithMaxSecretSize(tt.max), WithTruncateOversized())
+
+			got, truncated, err := c.checkSize(context.Background(), tt.secret)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || truncated != (got != tt.secret) {
+				t.Errorf("checkSize(%q) = %q, %v, want %q", tt.secret, got, truncated, tt.want)
+			}
+		})
+	}
+}
+
+// recordingLogger is a Logger which records the messages it receives.
+type recordingLogger struct {
+	msgs []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) {
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestCreateReportsTruncation(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+	logger := &recordingLogger{}
+	c := ts.client(WithMaxSecretSize(4), WithTruncateOversized(), WithLogger(logger))
+
+	s, err := c.Create("hunter2", "", "", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !s.Truncated {
+		t.Error("Truncated = false, want true")
+	}
+	if got := ts.last(t).Form.Get("secret"); got != "hunt" {
+		t.Errorf("sent secret = %q, want hunt", got)
+	}
+	if len(logger.msgs) != 1 || logger.msgs[0] != "secret truncated" {
+		t.Errorf("logs = %q, want the truncation", logger.msgs)
+	}
+
+	s, err = c.Create("hunt", "", "", 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Truncated {
+		t.Error("Truncated = true for a secret which fits")
+	}
+}
+
+func TestCreateTooLarge(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+
+	_, err := ts.client(WithMaxSecretSize(4)).Create("hunter2", "", "", 60)
+	if !errors.Is(err, ErrSecretTooLarge) {
+		t.Errorf("error = %v, want ErrSecretTooLarge", err)
+	}
+	if n := ts.count(); n != 0 {
+		t.Errorf("the server received %d requests, want 0", n)
+	}
+}
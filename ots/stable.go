@@ -0,0 +1,60 @@
+package ots
+
+import (
+	"context"
+	"time"
+)
+
+// stablePolls is how many times the metadata is polled within a settle window.
+const stablePolls = 4
+
+// stableMaxWindows caps the total polling time of RetrieveStableMetadata as a multiple of the settle window.
+const stableMaxWindows = 10
+
+// RetrieveStableMetadata polls the metadata of a secret until its state has not changed for settleWindow, then returns
+// that snapshot. This avoids acting on a momentary state, such as just after creation. Polling stops when ctx is done,
+// or after ten settle windows, in which case the latest snapshot is returned along with ErrMetadataUnstable.
+func (c *Client) RetrieveStableMetadata(ctx context.Context, metadataKey string, settleWindow time.Duration) (*Secret, error) {
+
+	interval := settleWindow / stablePolls
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	deadline := time.Now().Add(stableMaxWindows * settleWindow)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		last  *Secret
+		since time.Time
+	)
+
+	for {
+		m, err := c.RetrieveMetadataContext(ctx, metadataKey)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		if last == nil || m.State != last.State {
+			since = now
+		}
+		last = m
+
+		if now.Sub(since) >= settleWindow {
+			return last, nil
+		}
+
+		if now.After(deadline) {
+			return last, ErrMetadataUnstable
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
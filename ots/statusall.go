@@ -0,0 +1,54 @@
+package ots
+
+import (
+	"context"
+	"sync"
+)
+
+// statusAllConcurrency is the most status requests StatusAll sends at once.
+const statusAllConcurrency = 8
+
+// StatusAll checks the status of each OTS server in baseURLs, such as https://ots.internal/api/v1, and returns a map
+// of each base URL to the error from its status check, which is nil when the server is online. Up to 8 servers are
+// checked at once. The status endpoint does not require an account, so the requests are anonymous. Any options,
+// such as WithTimeout, are applied to the client used for every server.
+//
+// If ctx is cancelled, the servers which have not yet been checked are reported with the context's error.
+func StatusAll(ctx context.Context, baseURLs []string, opts ...Option) map[string]error {
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]error, len(baseURLs))
+		sem     = make(chan struct{}, statusAllConcurrency)
+	)
+
+	for _, baseURL := range baseURLs {
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			results[baseURL] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(baseURL string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			c := NewWithURL("", "", baseURL, append([]Option{WithAnonymous()}, opts...)...)
+			err := c.StatusContext(ctx)
+
+			mu.Lock()
+			results[baseURL] = err
+			mu.Unlock()
+		}(baseURL)
+	}
+
+	wg.Wait()
+
+	return results
+}
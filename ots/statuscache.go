@@ -0,0 +1,70 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// statusCache holds the result of the last Status check for a fixed duration.
+type statusCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// WithStatusCache caches the result of Status for ttl, so repeated calls within that window return the cached
+// result rather than sending a request. This is useful when many goroutines check server availability in a hot path.
+// Use RefreshStatus to bypass the cache.
+func WithStatusCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.statusCache = &statusCache{ttl: ttl}
+	}
+}
+
+// RefreshStatus checks the current status of the OTS system, ignoring any cached result from WithStatusCache,
+// and caches the new result. A check which was cancelled or timed out by its context is not cached, as it says
+// nothing about the server.
+func (c *Client) RefreshStatus() error {
+	return c.RefreshStatusContext(context.Background())
+}
+
+// RefreshStatusContext is the same as RefreshStatus, but the request is bound to ctx.
+func (c *Client) RefreshStatusContext(ctx context.Context) error {
+
+	err := c.status(ctx)
+
+	if c.statusCache != nil && !contextError(ctx, err) {
+		c.statusCache.mu.Lock()
+		c.statusCache.checked = time.Now()
+		c.statusCache.err = err
+		c.statusCache.mu.Unlock()
+	}
+
+	return err
+}
+
+// cachedStatus returns the cached status result, if there is one which has not expired.
+func (c *Client) cachedStatus() (bool, error) {
+
+	if c.statusCache == nil {
+		return false, nil
+	}
+
+	c.statusCache.mu.Lock()
+	defer c.statusCache.mu.Unlock()
+
+	if c.statusCache.checked.IsZero() || time.Since(c.statusCache.checked) >= c.statusCache.ttl {
+		return false, nil
+	}
+
+	return true, c.statusCache.err
+}
+
+// contextError reports whether err was caused by ctx being cancelled or passing its deadline.
+func contextError(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
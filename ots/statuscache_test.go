@@ -0,0 +1,47 @@
+package ots
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatusCache(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+	c := ts.client(WithStatusCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if err := c.Status(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := ts.count(); n != 1 {
+		t.Errorf("the server received %d requests, want 1", n)
+	}
+
+	if err := c.RefreshStatus(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := ts.count(); n != 2 {
+		t.Errorf("the server received %d requests after refreshing, want 2", n)
+	}
+}
+
+func TestStatusCacheIgnoresCancelledChecks(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+	c := ts.client(WithStatusCache(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.StatusContext(ctx); err == nil {
+		t.Fatal("expected an error for a cancelled context")
+	}
+
+	if err := c.Status(); err != nil {
+		t.Errorf("the cancelled check was cached: %v", err)
+	}
+}
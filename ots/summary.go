@@ -0,0 +1,52 @@
+package ots
+
+import (
+	"context"
+	"time"
+)
+
+// SecretSummary is an interpretation of a secret's metadata at a point in time, as returned by Summary.
+type SecretSummary struct {
+	MetadataKey string
+
+	// The state reported by the server, such as "new", "received" or "burned".
+	State string
+
+	// Whether the recipient has viewed the secret.
+	Viewed bool
+
+	Created time.Time
+
+	// When the secret expires, zero if the server did not report a remaining TTL.
+	ExpiresAt time.Time
+
+	// How long until the secret expires, zero if it has expired or the expiry is unknown.
+	ExpiresIn time.Duration
+}
+
+// Summary fetches the metadata for a secret and returns when it was created, when it expires, its state and whether
+// it has been viewed, so that callers do not need to interpret the raw TTLs and states themselves.
+func (c *Client) Summary(ctx context.Context, metadataKey string) (*SecretSummary, error) {
+
+	m, err := c.RetrieveMetadataContext(ctx, metadataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	summary := &SecretSummary{
+		MetadataKey: metadataKey,
+		State:       m.State,
+		Viewed:      m.Viewed(),
+		Created:     m.CreatedTime(),
+	}
+
+	if !m.SecretExpiresAt.IsZero() {
+		summary.ExpiresAt = m.SecretExpiresAt
+		if d := m.SecretExpiresAt.Sub(now); d > 0 {
+			summary.ExpiresIn = d
+		}
+	}
+
+	return summary, nil
+}
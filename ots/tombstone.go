@@ -0,0 +1,57 @@
+package ots
+
+import (
+	"sync"
+	"time"
+)
+
+// TombstoneRecord records that a secret was burned, as returned by Tombstones. It never contains the secret's value.
+type TombstoneRecord struct {
+	MetadataKey string
+	BurnedAt    time.Time
+}
+
+// tombstones holds a record of each secret burned by the client.
+type tombstones struct {
+	mu      sync.Mutex
+	records []TombstoneRecord
+}
+
+// WithTombstones enables keeping a TombstoneRecord for every successful Burn, which are available from Tombstones.
+// This allows proving that a secret was revoked after the server has forgotten it. The records are held in memory,
+// so they start empty for each new client.
+func WithTombstones() Option {
+	return func(c *Client) {
+		c.tombstones = &tombstones{}
+	}
+}
+
+// Tombstones returns a record of each secret burned since the client was created, oldest first. The returned slice
+// is a copy, so it is safe to modify. It is empty unless the client was created with WithTombstones.
+func (c *Client) Tombstones() []TombstoneRecord {
+
+	records := []TombstoneRecord{}
+	if c.tombstones == nil {
+		return records
+	}
+
+	c.tombstones.mu.Lock()
+	defer c.tombstones.mu.Unlock()
+
+	return append(records, c.tombstones.records...)
+}
+
+// recordTombstone records a successful burn, if tombstones are enabled.
+func (c *Client) recordTombstone(metadataKey string) {
+
+	if c.tombstones == nil {
+		return
+	}
+
+	c.tombstones.mu.Lock()
+	c.tombstones.records = append(c.tombstones.records, TombstoneRecord{
+		MetadataKey: metadataKey,
+		BurnedAt:    time.Now().UTC(),
+	})
+	c.tombstones.mu.Unlock()
+}
@@ -0,0 +1,98 @@
+package ots
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TraceInfo is the timing breakdown of a single request to the OTS API, reported to the function given to WithHTTPTrace.
+// A phase which did not happen, such as DNS lookup when a connection is reused, has a zero duration.
+type TraceInfo struct {
+
+	// Time spent resolving the host name.
+	DNSLookup time.Duration
+
+	// Time spent establishing the TCP connection.
+	Connect time.Duration
+
+	// Time spent on the TLS handshake.
+	TLSHandshake time.Duration
+
+	// Time from the request being written to the first byte of the response.
+	TimeToFirstByte time.Duration
+
+	// Time from starting the request to the response headers being received.
+	Total time.Duration
+
+	// Whether an idle connection from the pool was used.
+	ConnReused bool
+}
+
+// WithHTTPTrace registers a function which is called with a TraceInfo after each request, useful for diagnosing
+// whether latency against a server is caused by DNS, the TLS handshake or the server itself.
+func WithHTTPTrace(fn func(TraceInfo)) Option {
+	return func(c *Client) {
+		c.traceFn = fn
+	}
+}
+
+// withTrace attaches an httptrace.ClientTrace to the request, the returned function reports the collected timings.
+// net/http can call the trace hooks for a dial from its own goroutine, even after the request has returned, so the
+// timings are guarded by a mutex and reported from a copy.
+func withTrace(req *http.Request, fn func(TraceInfo)) (*http.Request, func()) {
+
+	var mu sync.Mutex
+	var info TraceInfo
+	var start, dnsStart, connStart, tlsStart, wrote time.Time
+
+	record := func(f func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		f()
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) { record(func() { start = time.Now() }) },
+		GotConn: func(ci httptrace.GotConnInfo) { record(func() { info.ConnReused = ci.Reused }) },
+		DNSStart: func(httptrace.DNSStartInfo) {
+			record(func() { dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func() { info.DNSLookup = time.Since(dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			record(func() { connStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			record(func() { info.Connect = time.Since(connStart) })
+		},
+		TLSHandshakeStart: func() {
+			record(func() { tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { info.TLSHandshake = time.Since(tlsStart) })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func() { wrote = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			record(func() { info.TimeToFirstByte = time.Since(wrote) })
+		},
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	return req, func() {
+		mu.Lock()
+		reported := info
+		if !start.IsZero() {
+			reported.Total = time.Since(start)
+		}
+		mu.Unlock()
+
+		fn(reported)
+	}
+}
@@ -0,0 +1,51 @@
+package ots
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestHTTPTrace(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"status":"nominal"}`))
+
+	var mu sync.Mutex
+	var traces []TraceInfo
+	c := ts.client(WithHTTPTrace(func(info TraceInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		traces = append(traces, info)
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Status(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := c.Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(traces) != 6 {
+		t.Fatalf("got %d traces, want 6", len(traces))
+	}
+	for _, info := range traces {
+		if info.Total <= 0 {
+			t.Errorf("trace %+v has no total", info)
+		}
+	}
+	if last := traces[len(traces)-1]; !last.ConnReused {
+		t.Errorf("the last request did not reuse a connection: %+v", last)
+	}
+}
@@ -0,0 +1,192 @@
+package ots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// trackingRecordVersion is the version of the binary encoding written by TrackingRecord.MarshalBinary.
+// Version 1 records, which have no challenge, and version 2 records, whose challenge has no iteration count, can
+// still be decoded.
+const trackingRecordVersion = 3
+
+// TrackingRecord is the minimal information needed to keep track of a secret after it has been created, as returned
+// by Secret.TrackingRecord. It never contains the secret value or passphrase, so it is safe to persist.
+type TrackingRecord struct {
+	MetadataKey string
+
+	// Unix time the secret was created.
+	Created int64
+
+	// SHA-256 hash of the secret's recipients, empty if there were none.
+	RecipientHash []byte
+
+	// Time to live of the secret in seconds, as specified on creation.
+	TTL int
+
+	// The recipient's challenge, if one was given in CreateOptions.
+	Challenge *ChallengeRecord
+}
+
+// TrackingRecord returns the information needed to track the secret, for services which persist metadata keys.
+func (s *Secret) TrackingRecord() TrackingRecord {
+
+	r := TrackingRecord{
+		MetadataKey: s.MetadataKey,
+		Created:     s.Created,
+		TTL:         s.TTL,
+		Challenge:   s.Challenge,
+	}
+
+	if len(s.Recipient) > 0 {
+		sum := sha256.Sum256([]byte(strings.Join(s.Recipient, "\n")))
+		r.RecipientHash = sum[:]
+	}
+
+	return r
+}
+
+// MarshalBinary encodes the record in a compact, versioned binary form for storage.
+func (r TrackingRecord) MarshalBinary() ([]byte, error) {
+
+	var buf bytes.Buffer
+	tmp := make([]byte, binary.MaxVarintLen64)
+
+	buf.WriteByte(trackingRecordVersion)
+
+	buf.Write(tmp[:binary.PutUvarint(tmp, uint64(len(r.MetadataKey)))])
+	buf.WriteString(r.MetadataKey)
+
+	buf.Write(tmp[:binary.PutVarint(tmp, r.Created)])
+
+	buf.Write(tmp[:binary.PutUvarint(tmp, uint64(len(r.RecipientHash)))])
+	buf.Write(r.RecipientHash)
+
+	buf.Write(tmp[:binary.PutVarint(tmp, int64(r.TTL))])
+
+	if r.Challenge == nil {
+		buf.WriteByte(0)
+		return buf.Bytes(), nil
+	}
+
+	buf.WriteByte(1)
+	for _, b := range [][]byte{[]byte(r.Challenge.Question), r.Challenge.Salt, r.Challenge.AnswerHash} {
+		buf.Write(tmp[:binary.PutUvarint(tmp, uint64(len(b)))])
+		buf.Write(b)
+	}
+	buf.Write(tmp[:binary.PutUvarint(tmp, uint64(r.Challenge.Iterations))])
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a record written by MarshalBinary.
+func (r *TrackingRecord) UnmarshalBinary(data []byte) error {
+
+	buf := bytes.NewReader(data)
+
+	version, err := buf.ReadByte()
+	if err != nil {
+		return errors.New("ots: empty tracking record")
+	}
+	if version < 1 || version > trackingRecordVersion {
+		return fmt.Errorf("ots: unsupported tracking record version %d", version)
+	}
+
+	key, err := readBytes(buf)
+	if err != nil {
+		return fmt.Errorf("ots: decoding tracking record metadata key: %w", err)
+	}
+
+	created, err := binary.ReadVarint(buf)
+	if err != nil {
+		return fmt.Errorf("ots: decoding tracking record created time: %w", err)
+	}
+
+	hash, err := readBytes(buf)
+	if err != nil {
+		return fmt.Errorf("ots: decoding tracking record recipient hash: %w", err)
+	}
+
+	ttl, err := binary.ReadVarint(buf)
+	if err != nil {
+		return fmt.Errorf("ots: decoding tracking record TTL: %w", err)
+	}
+
+	var challenge *ChallengeRecord
+	if version >= 2 {
+		if challenge, err = readChallenge(buf, version); err != nil {
+			return fmt.Errorf("ots: decoding tracking record challenge: %w", err)
+		}
+	}
+
+	r.MetadataKey = string(key)
+	r.Created = created
+	r.RecipientHash = nil
+	if len(hash) > 0 {
+		r.RecipientHash = hash
+	}
+	r.TTL = int(ttl)
+	r.Challenge = challenge
+
+	return nil
+}
+
+// readChallenge reads an optional challenge, which is preceded by a byte indicating whether it is present. The
+// iteration count is only read for version 3 records and later.
+func readChallenge(buf *bytes.Reader, version byte) (*ChallengeRecord, error) {
+
+	present, err := buf.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if present == 0 {
+		return nil, nil
+	}
+
+	var fields [3][]byte
+	for i := range fields {
+		if fields[i], err = readBytes(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge := &ChallengeRecord{Question: string(fields[0]), Salt: fields[1], AnswerHash: fields[2]}
+
+	if version >= 3 {
+		iterations, err := binary.ReadUvarint(buf)
+		if err != nil {
+			return nil, err
+		}
+		if iterations > math.MaxInt32 {
+			return nil, fmt.Errorf("iteration count %d is too large", iterations)
+		}
+		challenge.Iterations = int(iterations)
+	}
+
+	return challenge, nil
+}
+
+// readBytes reads a length-prefixed byte slice.
+func readBytes(buf *bytes.Reader) ([]byte, error) {
+
+	n, err := binary.ReadUvarint(buf)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(buf.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(buf, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
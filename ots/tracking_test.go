@@ -0,0 +1,77 @@
+package ots
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestTrackingRecordRoundTrip(t *testing.T) {
+
+	challenge := &ChallengeRecord{Question: "First pet?", Salt: []byte("salt"), AnswerHash: []byte("hash"), Iterations: 1000}
+
+	for _, r := range []TrackingRecord{
+		{MetadataKey: "metakey", Created: 1700000000, TTL: 3600},
+		{MetadataKey: "metakey", Created: 1700000000, TTL: 3600, RecipientHash: []byte("recipients"), Challenge: challenge},
+	} {
+		b, err := r.MarshalBinary()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b[0] != trackingRecordVersion {
+			t.Errorf("version = %d, want %d", b[0], trackingRecordVersion)
+		}
+
+		var got TrackingRecord
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, r) {
+			t.Errorf("round trip = %+v, want %+v", got, r)
+		}
+	}
+}
+
+func TestTrackingRecordVersion2(t *testing.T) {
+
+	salt := []byte("salt")
+	sum := sha256.Sum256(append(append([]byte{}, salt...), "rex"...))
+
+	// A version 2 record with a challenge, which has no iteration count.
+	tmp := make([]byte, binary.MaxVarintLen64)
+	b := []byte{2}
+	b = append(b, tmp[:binary.PutUvarint(tmp, 7)]...)
+	b = append(b, "metakey"...)
+	b = append(b, tmp[:binary.PutVarint(tmp, 1700000000)]...)
+	b = append(b, 0)
+	b = append(b, tmp[:binary.PutVarint(tmp, 3600)]...)
+	b = append(b, 1)
+	for _, field := range [][]byte{[]byte("First pet?"), salt, sum[:]} {
+		b = append(b, tmp[:binary.PutUvarint(tmp, uint64(len(field)))]...)
+		b = append(b, field...)
+	}
+
+	var r TrackingRecord
+	if err := r.UnmarshalBinary(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.MetadataKey != "metakey" || r.TTL != 3600 || r.Challenge == nil || r.Challenge.Iterations != 0 {
+		t.Fatalf("record = %+v", r)
+	}
+	if !r.Challenge.Verify("Rex") {
+		t.Error("the version 2 challenge did not verify")
+	}
+}
+
+func TestTrackingRecordUnsupportedVersion(t *testing.T) {
+
+	var r TrackingRecord
+	if err := r.UnmarshalBinary([]byte{trackingRecordVersion + 1}); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+	if err := r.UnmarshalBinary(nil); err == nil {
+		t.Error("expected an error for an empty record")
+	}
+}
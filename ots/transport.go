@@ -0,0 +1,106 @@
+package ots
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithDialTimeout sets how long to wait for a TCP connection to the server to be established. This allows failing
+// fast on connection setup while still allowing a slow response. The default is the 30 seconds used by net/http.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.dialTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout sets how long to wait for the TLS handshake with the server to complete. The default is
+// the 10 seconds used by net/http.
+func WithTLSHandshakeTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.tlsHandshakeTimeout = d
+	}
+}
+
+// defaultTimeout is the overall timeout of requests when the client is not given its own HTTP client.
+const defaultTimeout = 30 * time.Second
+
+// defaultHTTPClient is used by clients which were not created with New, such as a Client literal.
+var defaultHTTPClient = &http.Client{Timeout: defaultTimeout}
+
+// WithHTTPClient sets the HTTP client used to send requests, allowing its timeout, transport and proxy to be
+// configured, or an instrumented transport to be used for tracing. By default, a client with a 30 second timeout
+// is used. WithDialTimeout and WithTLSHandshakeTimeout are applied to a copy of hc when its transport is an
+// *http.Transport or nil, and are ignored for other transports.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.hc = hc
+	}
+}
+
+// WithTimeout sets the overall timeout of a request, including reading the response. A timeout of zero means no
+// timeout. If WithHTTPClient is also used, the timeout is applied to a copy of that client. The default is 30 seconds.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+		c.timeoutSet = true
+	}
+}
+
+// configureTransport creates the default HTTP client if none was given, and applies any transport options to it.
+func (c *Client) configureTransport() {
+
+	if c.hc == nil {
+		c.hc = &http.Client{Timeout: defaultTimeout}
+	}
+
+	if c.timeoutSet && c.hc.Timeout != c.timeout {
+		hc := *c.hc
+		hc.Timeout = c.timeout
+		c.hc = &hc
+	}
+
+	if c.dialTimeout == 0 && c.tlsHandshakeTimeout == 0 {
+		return
+	}
+
+	var t *http.Transport
+	switch rt := c.hc.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		return
+	}
+
+	if c.dialTimeout != 0 {
+		t.DialContext = (&net.Dialer{
+			Timeout:   c.dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+
+	if c.tlsHandshakeTimeout != 0 {
+		t.TLSHandshakeTimeout = c.tlsHandshakeTimeout
+	}
+
+	hc := *c.hc
+	hc.Transport = t
+	c.hc = &hc
+}
+
+// httpClient returns the HTTP client used to send requests.
+func (c *Client) httpClient() *http.Client {
+	if c.hc != nil {
+		return c.hc
+	}
+	return defaultHTTPClient
+}
+
+// Close closes any idle connections held by the client's HTTP transport, such as on shutdown or at the end of a
+// test. It is optional, as idle connections are eventually closed by the transport, but recommended for
+// applications which create many clients. The client remains usable afterwards and opens new connections as needed.
+func (c *Client) Close() {
+	c.httpClient().CloseIdleConnections()
+}
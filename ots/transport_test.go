@@ -0,0 +1,48 @@
+package ots
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewInitialisesHTTPClient(t *testing.T) {
+
+	tests := []struct {
+		name string
+		c    *Client
+		want time.Duration
+	}{
+		{name: "New", c: New("user@example.com", "token"), want: defaultTimeout},
+		{name: "NewClient", c: NewClient(), want: defaultTimeout},
+		{name: "NewWithURL", c: NewWithURL("user@example.com", "token", "https://ots.internal/api/v1"), want: defaultTimeout},
+		{name: "WithTimeout", c: New("user@example.com", "token", WithTimeout(5*time.Second)), want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.c.hc == nil {
+				t.Fatal("the HTTP client was not initialised")
+			}
+			if tt.c.hc == defaultHTTPClient || tt.c.hc == http.DefaultClient {
+				t.Error("the client shares a package-level HTTP client")
+			}
+			if tt.c.hc.Timeout != tt.want {
+				t.Errorf("timeout = %s, want %s", tt.c.hc.Timeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithHTTPClientTimeoutCopies(t *testing.T) {
+
+	hc := &http.Client{Timeout: time.Minute}
+	c := New("user@example.com", "token", WithHTTPClient(hc), WithTimeout(time.Second))
+
+	if c.hc.Timeout != time.Second {
+		t.Errorf("timeout = %s, want 1s", c.hc.Timeout)
+	}
+	if hc.Timeout != time.Minute {
+		t.Errorf("the given HTTP client was modified, its timeout is %s", hc.Timeout)
+	}
+}
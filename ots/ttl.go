@@ -0,0 +1,64 @@
+package ots
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultTTLs are the expiry presets offered by the public OTS web UI.
+var defaultTTLs = []time.Duration{
+	5 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	4 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// SupportedTTLs returns the TTL choices which can be offered when creating a secret, shortest first, such as for a
+// dropdown in a web form. The v1 API does not expose the TTL presets or account limits of a server, so the presets
+// of the public OTS service are returned, leaving out any longer than the maximum set by WithMaxTTL so that every
+// choice is accepted by Create. The returned slice is a copy and can be modified by the caller.
+func (c *Client) SupportedTTLs(ctx context.Context) ([]time.Duration, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ttls := make([]time.Duration, 0, len(defaultTTLs))
+	for _, ttl := range defaultTTLs {
+		if c.maxTTL > 0 && ttl > c.maxTTL {
+			continue
+		}
+		ttls = append(ttls, ttl)
+	}
+
+	return ttls, nil
+}
+
+// WithMaxTTL sets the longest TTL which Create and Generate accept, such as the 7 days allowed by a free account on
+// the public OTS service. A longer TTL is rejected with ErrInvalidTTL before a request is sent, rather than being
+// rejected or silently capped by the server. There is no maximum by default.
+func WithMaxTTL(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxTTL = d
+	}
+}
+
+// checkTTL returns ErrInvalidTTL if ttl, in seconds, is negative or longer than the maximum set by WithMaxTTL.
+// A ttl of zero is valid, as it is left out of the request so that the server's default is used.
+func (c *Client) checkTTL(ttl int) error {
+
+	if ttl < 0 {
+		return fmt.Errorf("%w: %d seconds must not be negative", ErrInvalidTTL, ttl)
+	}
+
+	if c.maxTTL > 0 && time.Duration(ttl)*time.Second > c.maxTTL {
+		return fmt.Errorf("%w: %d seconds is longer than the maximum of %s", ErrInvalidTTL, ttl, c.maxTTL)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package ots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSupportedTTLs(t *testing.T) {
+
+	ttls, err := New("user@example.com", "token").SupportedTTLs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ttls, defaultTTLs) {
+		t.Errorf("SupportedTTLs = %v, want %v", ttls, defaultTTLs)
+	}
+
+	ttls[0] = 0
+	if defaultTTLs[0] == 0 {
+		t.Error("modifying the result changed the presets")
+	}
+}
+
+func TestSupportedTTLsWithMaxTTL(t *testing.T) {
+
+	ts := newTestServer(t, respond(http.StatusOK, `{"metadata_key":"metakey"}`))
+	c := ts.client(WithMaxTTL(24 * time.Hour))
+
+	ttls, err := c.SupportedTTLs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ttls) == 0 || ttls[len(ttls)-1] != 24*time.Hour {
+		t.Errorf("SupportedTTLs = %v, want presets up to 24h", ttls)
+	}
+
+	for _, ttl := range ttls {
+		if _, err := c.Create("hunter2", "", "", int(ttl/time.Second)); err != nil {
+			t.Errorf("Create with the supported TTL %s: %v", ttl, err)
+		}
+	}
+
+	if _, err := c.Create("hunter2", "", "", int(72*time.Hour/time.Second)); !errors.Is(err, ErrInvalidTTL) {
+		t.Errorf("error = %v, want ErrInvalidTTL", err)
+	}
+}
+
+func TestSupportedTTLsCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := New("user@example.com", "token").SupportedTTLs(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,48 @@
+package ots
+
+import "sync"
+
+// ttlStats counts how many secrets have been created with each TTL.
+type ttlStats struct {
+	mu     sync.Mutex
+	counts map[int]int
+}
+
+// WithTTLStats enables counting of the TTLs used by successful calls to Create, which are available from TTLStats.
+// The counts are held in memory, so they start empty for each new client.
+func WithTTLStats() Option {
+	return func(c *Client) {
+		c.ttlStats = &ttlStats{counts: make(map[int]int)}
+	}
+}
+
+// TTLStats returns how many secrets have been created with each TTL, in seconds, since the client was created.
+// The returned map is a copy, so it is safe to modify. It is empty unless the client was created with WithTTLStats.
+func (c *Client) TTLStats() map[int]int {
+
+	stats := make(map[int]int)
+	if c.ttlStats == nil {
+		return stats
+	}
+
+	c.ttlStats.mu.Lock()
+	defer c.ttlStats.mu.Unlock()
+
+	for ttl, n := range c.ttlStats.counts {
+		stats[ttl] = n
+	}
+
+	return stats
+}
+
+// recordTTL counts a successful create with the given TTL, if TTL stats are enabled.
+func (c *Client) recordTTL(ttl int) {
+
+	if c.ttlStats == nil {
+		return
+	}
+
+	c.ttlStats.mu.Lock()
+	c.ttlStats.counts[ttl]++
+	c.ttlStats.mu.Unlock()
+}
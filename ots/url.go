@@ -0,0 +1,162 @@
+package ots
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BuildShareURL returns the link a recipient visits to view a secret, for example https://onetimesecret.com/secret/SECRET_KEY.
+// The baseURL is the API base the secret was created with, such as https://onetimesecret.com/api/v1, and is converted
+// into the web UI base. An empty baseURL uses the public OTS service. This does not require a Client, so it can be used
+// anywhere that only has the secret key stored.
+func BuildShareURL(baseURL, secretKey string) string {
+	return webBase(baseURL) + "/secret/" + secretKey
+}
+
+// SecretURL returns the link a recipient visits to view the secret, using the client's API base, such as
+// https://onetimesecret.com/secret/SECRET_KEY for the public OTS service. Self-hosted bases are handled in the same
+// way as BuildShareURL. If the server reported a SharePath, that path is used instead of /secret/SECRET_KEY. An
+// absolute SharePath is only used if IsTrustedURL accepts it, so that a server cannot send recipients to another
+// host, otherwise the link is built from the secret key as usual.
+func (c *Client) SecretURL(s *Secret) string {
+
+	if s.SharePath == "" {
+		return BuildShareURL(c.baseURL(), s.SecretKey)
+	}
+
+	if u, err := url.Parse(s.SharePath); err == nil && u.IsAbs() {
+		if c.IsTrustedURL(s.SharePath) {
+			return s.SharePath
+		}
+		return BuildShareURL(c.baseURL(), s.SecretKey)
+	}
+
+	return webBase(c.baseURL()) + "/" + strings.TrimLeft(s.SharePath, "/")
+}
+
+// BuildMetadataURL returns the private link for viewing a secret's metadata, for example https://onetimesecret.com/private/METADATA_KEY.
+// This link should only be used by the creator of the secret. The baseURL is handled in the same way as BuildShareURL.
+func BuildMetadataURL(baseURL, metadataKey string) string {
+	return webBase(baseURL) + "/private/" + metadataKey
+}
+
+// apiSuffix matches the API path at the end of an API base, such as /api/v1 or /api.
+var apiSuffix = regexp.MustCompile(`(?i)/api(/v[0-9]+)?$`)
+
+// WebBaseFromAPIBase returns the base of the web UI for an API base, so https://ots.internal/api/v1 becomes
+// https://ots.internal. Trailing slashes and any API version are handled, and a base which is hosted under a
+// path, such as https://example.com/ots/api/v2, keeps that path. A base without an API path is returned without
+// its trailing slashes. An error is returned if apiBase is not an absolute URL.
+func WebBaseFromAPIBase(apiBase string) (string, error) {
+
+	u, err := url.Parse(strings.TrimSpace(apiBase))
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("ots: %q is not an absolute URL", apiBase)
+	}
+
+	u.Path = apiSuffix.ReplaceAllString(strings.TrimRight(u.Path, "/"), "")
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String(), nil
+}
+
+// webBase converts an API base into the base of the web UI, using the public OTS service when apiBase is empty.
+// If apiBase cannot be parsed, it is used with only its trailing slashes removed.
+func webBase(apiBase string) string {
+
+	if apiBase == "" {
+		apiBase = base
+	}
+
+	web, err := WebBaseFromAPIBase(apiBase)
+	if err != nil {
+		return strings.TrimRight(apiBase, "/")
+	}
+
+	return web
+}
+
+// ParseSecretKey returns the secret key from either a bare key or a share URL, such as
+// https://onetimesecret.com/secret/SECRET_KEY, as a recipient may have been given either. Surrounding whitespace
+// is ignored. ErrInvalidSecretKey is returned when the input is neither a valid key nor a recognised share URL.
+func ParseSecretKey(keyOrURL string) (string, error) {
+
+	keyOrURL = strings.TrimSpace(keyOrURL)
+
+	if isSecretKey(keyOrURL) {
+		return keyOrURL, nil
+	}
+
+	u, err := url.Parse(keyOrURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: %q is not a secret key or share URL", ErrInvalidSecretKey, keyOrURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] != "secret" || !isSecretKey(parts[len(parts)-1]) {
+		return "", fmt.Errorf("%w: %q is not a share URL", ErrInvalidSecretKey, keyOrURL)
+	}
+
+	return parts[len(parts)-1], nil
+}
+
+// IsTrustedURL reports whether raw is an http or https URL on the same host as the client's base URL or one of its
+// fallback base URLs. Hosts are compared case-insensitively and include the port, if any.
+func (c *Client) IsTrustedURL(raw string) bool {
+
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+
+	for _, b := range append([]string{c.baseURL()}, c.fallbackBases...) {
+		trusted, err := url.Parse(b)
+		if err == nil && strings.EqualFold(trusted.Host, u.Host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSecretKey reports whether s has the form of an OTS key, which is alphanumeric.
+func isSecretKey(s string) bool {
+
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MailtoLink returns a mailto: link which opens an email to recipient, with the subject and a body containing the
+// link to view the secret, for sharing a secret manually rather than through OTS. The baseURL is handled in the same
+// way as BuildShareURL. The recipient, subject and body are percent-encoded as required by RFC 6068.
+func (s *Secret) MailtoLink(recipient, baseURL, subject string) string {
+
+	body := "You have been sent a secret, it can only be viewed once:\r\n\r\n" + BuildShareURL(baseURL, s.SecretKey)
+
+	// The @ of the address is left as it is, as some email clients do not decode it.
+	return "mailto:" + strings.ReplaceAll(mailtoEscape(recipient), "%40", "@") +
+		"?subject=" + mailtoEscape(subject) +
+		"&body=" + mailtoEscape(body)
+}
+
+// mailtoEscape percent-encodes s for use in a mailto: link, where spaces must be %20 rather than +.
+func mailtoEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,115 @@
+package ots
+
+import "testing"
+
+func TestBuildURLs(t *testing.T) {
+
+	tests := []struct {
+		baseURL  string
+		share    string
+		metadata string
+	}{
+		{
+			baseURL:  "",
+			share:    "https://onetimesecret.com/secret/secretkey",
+			metadata: "https://onetimesecret.com/private/metakey",
+		},
+		{
+			baseURL:  "https://onetimesecret.com/api/v1",
+			share:    "https://onetimesecret.com/secret/secretkey",
+			metadata: "https://onetimesecret.com/private/metakey",
+		},
+		{
+			baseURL:  "https://ots.internal/api/v1/",
+			share:    "https://ots.internal/secret/secretkey",
+			metadata: "https://ots.internal/private/metakey",
+		},
+		{
+			baseURL:  "https://example.com/ots/api",
+			share:    "https://example.com/ots/secret/secretkey",
+			metadata: "https://example.com/ots/private/metakey",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := BuildShareURL(tt.baseURL, "secretkey"); got != tt.share {
+			t.Errorf("BuildShareURL(%q) = %q, want %q", tt.baseURL, got, tt.share)
+		}
+		if got := BuildMetadataURL(tt.baseURL, "metakey"); got != tt.metadata {
+			t.Errorf("BuildMetadataURL(%q) = %q, want %q", tt.baseURL, got, tt.metadata)
+		}
+	}
+}
+
+func TestWebBaseFromAPIBase(t *testing.T) {
+
+	tests := []struct {
+		apiBase string
+		want    string
+		wantErr bool
+	}{
+		{apiBase: "https://onetimesecret.com/api/v1", want: "https://onetimesecret.com"},
+		{apiBase: "https://ots.internal/api/v2/", want: "https://ots.internal"},
+		{apiBase: "https://ots.internal/API", want: "https://ots.internal"},
+		{apiBase: "https://ots.internal:8443/api/v1", want: "https://ots.internal:8443"},
+		{apiBase: "https://example.com/ots/api/v2", want: "https://example.com/ots"},
+		{apiBase: "https://example.com/ots/", want: "https://example.com/ots"},
+		{apiBase: " https://ots.internal/api/v1?x=1#y ", want: "https://ots.internal"},
+		{apiBase: "ots.internal/api/v1", wantErr: true},
+		{apiBase: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := WebBaseFromAPIBase(tt.apiBase)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WebBaseFromAPIBase(%q) error = %v, want error %v", tt.apiBase, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("WebBaseFromAPIBase(%q) = %q, want %q", tt.apiBase, got, tt.want)
+		}
+	}
+}
+
+func TestMailtoLink(t *testing.T) {
+
+	s := &Secret{SecretKey: "secretkey"}
+
+	got := s.MailtoLink("bob+ots@example.com", "https://ots.internal/api/v1", "Your new password & login")
+
+	want := "mailto:bob%2Bots@example.com" +
+		"?subject=Your%20new%20password%20%26%20login" +
+		"&body=You%20have%20been%20sent%20a%20secret%2C%20it%20can%20only%20be%20viewed%20once%3A%0D%0A%0D%0A" +
+		"https%3A%2F%2Fots.internal%2Fsecret%2Fsecretkey"
+	if got != want {
+		t.Errorf("MailtoLink = %q, want %q", got, want)
+	}
+}
+
+func TestSecretURL(t *testing.T) {
+
+	c := NewWithURL("user@example.com", "token", "https://ots.internal/api/v1",
+		WithFallbackBaseURLs("https://ots-backup.internal/api/v1"))
+
+	tests := []struct {
+		name      string
+		sharePath string
+		want      string
+	}{
+		{name: "no share path", want: "https://ots.internal/secret/secretkey"},
+		{name: "relative share path", sharePath: "/s/secretkey", want: "https://ots.internal/s/secretkey"},
+		{name: "trusted absolute", sharePath: "https://OTS.internal/s/secretkey", want: "https://OTS.internal/s/secretkey"},
+		{name: "fallback host", sharePath: "https://ots-backup.internal/s/secretkey", want: "https://ots-backup.internal/s/secretkey"},
+		{name: "untrusted absolute", sharePath: "https://evil.example.com/secret/secretkey", want: "https://ots.internal/secret/secretkey"},
+		{name: "untrusted scheme", sharePath: "javascript:alert(1)", want: "https://ots.internal/secret/secretkey"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.SecretURL(&Secret{SecretKey: "secretkey", SharePath: tt.sharePath})
+			if got != tt.want {
+				t.Errorf("SecretURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
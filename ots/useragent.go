@@ -0,0 +1,41 @@
+package ots
+
+import "runtime/debug"
+
+// modulePath is the path of this module, used to find its version in the build info.
+const modulePath = "github.com/jdockerty/onetimesecret-go"
+
+// defaultUserAgent is sent with every request when no User-Agent is set with WithUserAgent, such as
+// onetimesecret-go/v1.2.0, so that server administrators can identify traffic from this library.
+var defaultUserAgent = "onetimesecret-go/" + moduleVersion()
+
+// moduleVersion returns the version of this module which has been built into the program, or "devel" when it is
+// unknown, such as when building the module itself.
+func moduleVersion() string {
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			if dep.Version != "" {
+				return dep.Version
+			}
+		}
+	}
+
+	return "devel"
+}
+
+// userAgentHeader returns the User-Agent sent with requests, which is the one set with WithUserAgent or the default.
+func (c *Client) userAgentHeader() string {
+	if c.userAgent != "" {
+		return c.userAgent
+	}
+	return defaultUserAgent
+}
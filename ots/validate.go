@@ -0,0 +1,90 @@
+package ots
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Validate checks the client's configuration and returns a *ConfigError listing every problem found, rather than
+// only the first. This is intended to be called once at startup so that misconfiguration is reported clearly
+// instead of through failed requests. A nil error is returned when the configuration is valid.
+func (c *Client) Validate() error {
+
+	var problems []error
+
+	if !c.anonymous && !c.hasCredentials() {
+		problems = append(problems, ErrMissingCredentials)
+	}
+
+	if c.recipientEncoding != RecipientScalar && c.recipientEncoding != RecipientArray {
+		problems = append(problems, fmt.Errorf("unknown recipient encoding %d", c.recipientEncoding))
+	}
+
+	if c.apiVersion != "" && c.apiVersion != APIV1 && c.apiVersion != APIV2 {
+		problems = append(problems, fmt.Errorf("unknown API version %q", c.apiVersion))
+	}
+
+	if c.maxSecretSize < 0 {
+		problems = append(problems, fmt.Errorf("max secret size must not be negative, got %d", c.maxSecretSize))
+	}
+
+	if c.truncateOversized && c.maxSecretSize == 0 {
+		problems = append(problems, errors.New("truncating oversized secrets requires a max secret size"))
+	}
+
+	if c.httpClient().Timeout < 0 {
+		problems = append(problems, fmt.Errorf("timeout must not be negative, got %s", c.httpClient().Timeout))
+	}
+
+	if c.dialTimeout < 0 {
+		problems = append(problems, fmt.Errorf("dial timeout must not be negative, got %s", c.dialTimeout))
+	}
+
+	if c.tlsHandshakeTimeout < 0 {
+		problems = append(problems, fmt.Errorf("TLS handshake timeout must not be negative, got %s", c.tlsHandshakeTimeout))
+	}
+
+	if c.maxTTL < 0 {
+		problems = append(problems, fmt.Errorf("max TTL must not be negative, got %s", c.maxTTL))
+	}
+
+	if c.maxRetries < 0 {
+		problems = append(problems, fmt.Errorf("max retries must not be negative, got %d", c.maxRetries))
+	}
+
+	if c.retryBackoff < 0 {
+		problems = append(problems, fmt.Errorf("retry backoff must not be negative, got %s", c.retryBackoff))
+	}
+
+	if err := validateBaseURL(c.baseURL()); err != nil {
+		problems = append(problems, fmt.Errorf("base URL: %w", err))
+	}
+
+	for _, u := range c.fallbackBases {
+		if err := validateBaseURL(u); err != nil {
+			problems = append(problems, fmt.Errorf("fallback base URL: %w", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+
+	return nil
+}
+
+// validateBaseURL checks that u is an absolute http or https URL.
+func validateBaseURL(u string) error {
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q is not an absolute http or https URL", u)
+	}
+
+	return nil
+}
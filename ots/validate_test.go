@@ -0,0 +1,33 @@
+package ots
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+
+	if err := NewWithURL("user@example.com", "token", "https://ots.internal/api/v1").Validate(); err != nil {
+		t.Errorf("unexpected error for a valid client: %v", err)
+	}
+
+	err := NewWithURL("", "", "ots.internal", WithMaxRetries(-1)).Validate()
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("error = %v, want a *ConfigError", err)
+	}
+	if len(configErr.Problems) != 3 {
+		t.Errorf("problems = %v, want 3", configErr.Problems)
+	}
+
+	if !errors.Is(err, ErrMissingCredentials) {
+		t.Errorf("errors.Is(%v, ErrMissingCredentials) = false, want true", err)
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		t.Errorf("errors.As matched a *url.Error which is not one of the problems")
+	}
+}
@@ -0,0 +1,34 @@
+package ots
+
+import (
+	"context"
+	"fmt"
+)
+
+// BurnAndVerify burns the secret and then fetches its metadata to confirm that its state is "burned", returning
+// an error if it is not. If the metadata has expired between the burn and the check, the secret can no longer
+// be read either, so this is treated as success.
+func (c *Client) BurnAndVerify(ctx context.Context, metadataKey string) error {
+
+	if _, err := c.BurnContext(ctx, metadataKey); err != nil {
+		return fmt.Errorf("burning secret: %w", err)
+	}
+
+	m, err := c.RetrieveMetadataContext(ctx, metadataKey)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("verifying burn: %w", err)
+	}
+
+	if m == nil || m.State != "burned" {
+		state := ""
+		if m != nil {
+			state = m.State
+		}
+		return fmt.Errorf("ots: secret state is %q after burn, expected \"burned\"", state)
+	}
+
+	return nil
+}
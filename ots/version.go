@@ -0,0 +1,64 @@
+package ots
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// APIVersion is the version of the OTS API which requests are sent to.
+type APIVersion string
+
+const (
+	// APIV1 is the original OTS API, at /api/v1. This is the default.
+	APIV1 APIVersion = "v1"
+
+	// APIV2 is the newer OTS API, at /api/v2, which nests secrets under a record key in its responses.
+	APIV2 APIVersion = "v2"
+)
+
+// WithAPIVersion sets the version of the OTS API to use. The version in the API path of the client's base URLs,
+// such as /api/v1, is replaced with the given version, and a base without a version, such as /api, has it added.
+// For APIV2, secrets are read from the record key of responses. Requests are sent to the same routes and with the
+// same basic authentication for either version, so this is only suitable for servers which accept both at /api/v2.
+func WithAPIVersion(v APIVersion) Option {
+	return func(c *Client) {
+		c.apiVersion = v
+	}
+}
+
+// versionedBase returns the API base with the version set by WithAPIVersion, or unchanged if none was set.
+func (c *Client) versionedBase(apiBase string) string {
+
+	if c.apiVersion == "" || !apiSuffix.MatchString(apiBase) {
+		return apiBase
+	}
+
+	return apiSuffix.ReplaceAllString(apiBase, "/api/"+string(c.apiVersion))
+}
+
+// unwrapRecord returns the secret from a v2 response, which is nested under record, or the body unchanged for
+// other versions or when there is no record.
+func (c *Client) unwrapRecord(body []byte) []byte {
+
+	if c.apiVersion != APIV2 {
+		return body
+	}
+
+	var resp struct {
+		Record  json.RawMessage `json:"record"`
+		Records json.RawMessage `json:"records"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return body
+	}
+
+	if record := bytes.TrimSpace(resp.Record); len(record) > 0 && record[0] == '{' {
+		return record
+	}
+
+	if records := bytes.TrimSpace(resp.Records); len(records) > 0 && records[0] == '[' {
+		return records
+	}
+
+	return body
+}
@@ -0,0 +1,58 @@
+package ots
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// Warmup establishes connections to the server before they are needed, by sending the given number of concurrent
+// status requests, so that the first real request does not pay for the connection and TLS setup. The connections
+// are left idle in the pool of the underlying transport, which only keeps as many per host as its
+// MaxIdleConnsPerHost allows, 2 by default. If any requests fail, an error is returned with the first failure.
+func (c *Client) Warmup(ctx context.Context, connections int) error {
+
+	if connections <= 0 {
+		return nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		first  error
+	)
+
+	for i := 0; i < connections; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := c.do(ctx, "GET", "status", nil)
+			if err == nil {
+				// The body must be read in full for the connection to be reused.
+				_, err = io.Copy(ioutil.Discard, resp.Body)
+				resp.Body.Close()
+			}
+
+			if err != nil {
+				mu.Lock()
+				failed++
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if first != nil {
+		return fmt.Errorf("ots: %d of %d warmup requests failed: %w", failed, connections, first)
+	}
+
+	return nil
+}
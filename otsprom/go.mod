@@ -0,0 +1,13 @@
+module github.com/jdockerty/onetimesecret-go/otsprom
+
+go 1.15
+
+require (
+	github.com/jdockerty/onetimesecret-go v0.1.0
+	github.com/prometheus/client_golang v1.11.1
+	github.com/prometheus/client_model v0.2.0
+)
+
+// Builds within this repository use the ots package alongside this module. Consumers ignore replace directives, so
+// the release required above must be a tagged version of this repository which contains WithMetricsHook.
+replace github.com/jdockerty/onetimesecret-go => ../
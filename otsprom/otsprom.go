@@ -0,0 +1,94 @@
+// Package otsprom provides Prometheus metrics for the ots client. It is a separate module so that the ots package
+// does not depend on the Prometheus client library.
+//
+// The following metrics are registered, all labelled by route, such as "share" or "secret/:key":
+//
+//	ots_requests_total{method, route, code}       counter of requests which received a response
+//	ots_request_errors_total{method, route, type} counter of failed requests, where type is one of
+//	                                              "canceled", "timeout", "transport", "client" or "server"
+//	ots_request_duration_seconds{method, route}   histogram of the time taken to receive a response
+package otsprom
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/jdockerty/onetimesecret-go/ots"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WithPrometheus returns an ots.Option which registers the client's metrics with registerer and records them for
+// every request. If the metrics are already registered, for example by another client, the existing ones are shared.
+// It panics if registration fails for any other reason, in the same way as prometheus.MustRegister.
+func WithPrometheus(registerer prometheus.Registerer) ots.Option {
+
+	requests := register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ots_requests_total",
+		Help: "Requests to the OTS API which received a response, by status code.",
+	}, []string{"method", "route", "code"})).(*prometheus.CounterVec)
+
+	failures := register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ots_request_errors_total",
+		Help: "Failed requests to the OTS API, by type of error.",
+	}, []string{"method", "route", "type"})).(*prometheus.CounterVec)
+
+	duration := register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ots_request_duration_seconds",
+		Help:    "Time taken to receive a response from the OTS API.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})).(*prometheus.HistogramVec)
+
+	return ots.WithMetricsHook(func(m ots.RequestMetrics) {
+
+		duration.WithLabelValues(m.Method, m.Route).Observe(m.Duration.Seconds())
+
+		if m.StatusCode != 0 {
+			requests.WithLabelValues(m.Method, m.Route, strconv.Itoa(m.StatusCode)).Inc()
+		}
+
+		if t := errorType(m); t != "" {
+			failures.WithLabelValues(m.Method, m.Route, t).Inc()
+		}
+	})
+}
+
+// register registers c, returning the existing collector if an identical one is already registered.
+func register(registerer prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+
+	return c
+}
+
+// errorType classifies a failed request, returning an empty string if it succeeded.
+func errorType(m ots.RequestMetrics) string {
+
+	if m.Err != nil {
+		var ne net.Error
+		switch {
+		case errors.Is(m.Err, context.Canceled):
+			return "canceled"
+		case errors.Is(m.Err, context.DeadlineExceeded), errors.As(m.Err, &ne) && ne.Timeout():
+			return "timeout"
+		default:
+			return "transport"
+		}
+	}
+
+	switch {
+	case m.StatusCode >= 500:
+		return "server"
+	case m.StatusCode >= 400:
+		return "client"
+	}
+
+	return ""
+}
@@ -0,0 +1,96 @@
+package otsprom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/jdockerty/onetimesecret-go/ots"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestWithPrometheus(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/secret/missing" {
+			http.Error(w, `{"message":"Unknown secret"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"nominal"}`))
+	}))
+	defer ts.Close()
+
+	reg := prometheus.NewRegistry()
+	c := ots.NewWithURL("user@example.com", "token", ts.URL, WithPrometheus(reg))
+
+	if err := c.Status(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Retrieve("missing", ""); err == nil {
+		t.Fatal("expected an error retrieving a missing secret")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string][]map[string]string{}
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			got[f.GetName()] = append(got[f.GetName()], labels(m))
+		}
+	}
+
+	want := map[string][]map[string]string{
+		"ots_requests_total": {
+			{"method": "GET", "route": "status", "code": "200"},
+			{"method": "POST", "route": "secret/:key", "code": "404"},
+		},
+		"ots_request_errors_total": {
+			{"method": "POST", "route": "secret/:key", "type": "client"},
+		},
+		"ots_request_duration_seconds": {
+			{"method": "GET", "route": "status"},
+			{"method": "POST", "route": "secret/:key"},
+		},
+	}
+
+	for _, series := range got {
+		sort.Slice(series, func(i, j int) bool { return series[i]["route"] > series[j]["route"] })
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metrics = %v, want %v", got, want)
+	}
+}
+
+func TestWithPrometheusSharesRegistration(t *testing.T) {
+
+	reg := prometheus.NewRegistry()
+
+	WithPrometheus(reg)
+	WithPrometheus(reg)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) != 0 {
+		t.Errorf("gathered %d families before any request, want 0", len(families))
+	}
+}
+
+// labels returns the label names and values of m.
+func labels(m *dto.Metric) map[string]string {
+
+	l := map[string]string{}
+	for _, p := range m.GetLabel() {
+		l[p.GetName()] = p.GetValue()
+	}
+
+	return l
+}